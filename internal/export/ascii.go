@@ -0,0 +1,131 @@
+// internal/export/ascii.go
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// ASCIIOptions controls the layout of a plain-text tab.
+type ASCIIOptions struct {
+	Width          int  `json:"width"`           // line width systems wrap at; 0 never wraps
+	Header         bool `json:"header"`          // name, artist, tempo, tuning and notes
+	MeasureNumbers bool `json:"measure_numbers"` // numbers above the first column of each measure
+}
+
+func DefaultASCIIOptions() ASCIIOptions {
+	return ASCIIOptions{Width: 80, Header: true}
+}
+
+// WriteASCII writes a tab as plain text: labelled string lines with bar
+// lines between measures, broken into systems that fit opts.Width.
+func WriteASCII(w io.Writer, tab *models.Tab, opts ASCIIOptions) error {
+	var b strings.Builder
+
+	if opts.Header {
+		title := songTitle(tab)
+		b.WriteString(title + "\n")
+		b.WriteString(strings.Repeat("=", len([]rune(title))) + "\n")
+		fmt.Fprintf(&b, "Tempo %d bpm | Tuning %s | %s\n\n", tab.Tempo, tab.TuningString(), tab.TimeSignature)
+	}
+
+	writeSystems(&b, tab, opts.Width, opts.MeasureNumbers)
+
+	if opts.Header && strings.TrimSpace(tab.Notes) != "" {
+		b.WriteString("\n" + strings.TrimSpace(tab.Notes) + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func songTitle(tab *models.Tab) string {
+	if tab.Artist == "" {
+		return tab.Name
+	}
+	return tab.Name + " - " + tab.Artist
+}
+
+// writeSystems writes a tab's lines as stacked systems of whole measures,
+// each prefixed with its string names, with a blank line between systems.
+// Measures the tab has no bar line after get one. A width of 0 puts the
+// whole tab in one system.
+func writeSystems(b *strings.Builder, tab *models.Tab, width int, numbers bool) {
+	labels := tab.TuningLabels()
+	prefix := len([]rune(labels[0])) + 1
+	top := []rune(tab.Content[0])
+
+	// Group measures into systems by their printed width
+	var systems [][]int
+	measures := tab.Measures()
+	used := 0
+	for i, m := range measures {
+		cols := m.Len()
+		if m.End > len(top) || top[m.End-1] != '|' {
+			cols++
+		}
+		if n := len(systems); n > 0 && (width <= 0 || used+cols <= width-prefix) {
+			systems[n-1] = append(systems[n-1], i)
+			used += cols
+			continue
+		}
+		systems = append(systems, []int{i})
+		used = cols
+	}
+
+	for i, system := range systems {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		var lines [6]strings.Builder
+		numberLine := []rune(strings.Repeat(" ", prefix))
+		for str := range lines {
+			lines[str].WriteString(labels[str] + "|")
+		}
+		for _, index := range system {
+			m := measures[index]
+			if numbers {
+				label := []rune(fmt.Sprint(index + 1))
+				col := len([]rune(lines[0].String()))
+				// Skip numbers that would run into the previous one
+				if len(strings.TrimRight(string(numberLine), " "))+1 < col {
+					numberLine = append(numberLine, []rune(strings.Repeat(" ", col-len(numberLine)))...)
+					numberLine = append(numberLine, label...)
+				}
+			}
+			for str, line := range tab.Content {
+				lines[str].WriteString(cells(line, m))
+			}
+			if m.End > len(top) || top[m.End-1] != '|' {
+				for str := range lines {
+					lines[str].WriteString("|")
+				}
+			}
+		}
+
+		if numbers {
+			b.WriteString(strings.TrimRight(string(numberLine), " ") + "\n")
+		}
+		for str := range lines {
+			b.WriteString(lines[str].String() + "\n")
+		}
+	}
+}
+
+// cells returns the columns of span in line, padding short lines with rests.
+func cells(line string, span models.Span) string {
+	runes := []rune(line)
+	var b strings.Builder
+	for pos := span.Start; pos < span.End; pos++ {
+		if pos < len(runes) {
+			b.WriteRune(runes[pos])
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,272 @@
+// internal/export/chordpro.go
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// ChordPro song sheets, the reverse of the importer's reading: a tab's
+// notes are written as the sheet, chords over lyrics becoming inline
+// chords, and the measures of its chord chart as {define}s.
+
+var (
+	// chordName matches a chord symbol such as "G", "F#m7" or "D/F#".
+	chordName = regexp.MustCompile(`^([A-G][#b]?)((?:maj|min|dim|aug|sus|add|m|M|[0-9]|[#b+()-])*)(?:/([A-G][#b]?))?$`)
+	// sectionLine matches a section heading of a sheet, "[Chorus]".
+	sectionLine = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+	// labelLine matches the notes lines kept for ChordPro directives.
+	labelLine = regexp.MustCompile(`^(Subtitle|Key|Composer|Lyricist|Album|Year|Copyright|Duration): (.+)$`)
+	// commentLine matches a comment on the sheet.
+	commentLine = regexp.MustCompile(`^Comment: (.+)$`)
+)
+
+// WriteChordPro writes a tab as a ChordPro song sheet: the title, artist,
+// tempo, time signature, capo and, when it is not standard, the tuning as
+// directives, then the notes. "[Section]" lines open sections that run to
+// the next blank line, "Comment: text" lines become {comment}s, and lines
+// of chord names are merged into the lyric line below them. A "Bar N:
+// chord" line naming a measure that holds just a chord on its first beat
+// becomes a {define} of that chord; the rest of the tab follows in a tab
+// section.
+func WriteChordPro(w io.Writer, tab *models.Tab) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "{title: %s}\n", tab.Name)
+	if tab.Artist != "" {
+		fmt.Fprintf(&b, "{artist: %s}\n", tab.Artist)
+	}
+	if tuning := tab.TuningString(); tuning != "E A D G B e" {
+		fmt.Fprintf(&b, "{meta: tuning %s}\n", tuning)
+	}
+	fmt.Fprintf(&b, "{tempo: %d}\n{time: %s}\n", tab.Tempo, tab.TimeSignature)
+
+	measures := tab.Measures()
+	charted := make(map[int]bool) // measures written as {define}s
+	var defines, lines []string
+	for _, line := range strings.Split(strings.TrimSpace(tab.Notes), "\n") {
+		if line == "" && len(lines) == 0 {
+			continue
+		}
+		if m := capoLine.FindStringSubmatch(line); m != nil {
+			fmt.Fprintf(&b, "{capo: %s}\n", m[1])
+			continue
+		}
+		if m := labelLine.FindStringSubmatch(line); m != nil {
+			fmt.Fprintf(&b, "{%s: %s}\n", strings.ToLower(m[1]), m[2])
+			continue
+		}
+		if m := barLine.FindStringSubmatch(line); m != nil && chordName.MatchString(m[2]) {
+			bar, _ := strconv.Atoi(m[1])
+			if bar >= 1 && bar <= len(measures) && !charted[bar-1] {
+				if define, ok := chordDefine(tab, measures[bar-1], m[2]); ok {
+					defines = append(defines, define)
+					charted[bar-1] = true
+					continue
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+	for _, define := range defines {
+		b.WriteString(define + "\n")
+	}
+
+	b.WriteString("\n")
+	writeChordProSheet(&b, lines)
+
+	// The measures that are not a chord of the chart
+	rest := *tab
+	rest.Content = [6]string{}
+	for i, m := range measures {
+		if charted[i] {
+			continue
+		}
+		for str, line := range tab.Content {
+			rest.Content[str] += cells(line, m)
+		}
+	}
+	if len(measures) > len(charted) && !blankContent(rest.Content) {
+		b.WriteString("\n{start_of_tab}\n")
+		writeSystems(&b, &rest, 80, false)
+		b.WriteString("{end_of_tab}\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// chordDefine writes the chord in measure m as a {define}, if the measure
+// holds nothing but one chord at its start. The shape is written from base
+// fret 1 when it fits in the first five frets, and otherwise from its
+// lowest fret.
+func chordDefine(tab *models.Tab, m models.Span, name string) (string, bool) {
+	frets := [6]int{-1, -1, -1, -1, -1, -1}
+	played := false
+	for str, line := range tab.Content {
+		for _, n := range models.ParseNotes(cells(line, m), str) {
+			if n.Position != 0 {
+				return "", false
+			}
+			frets[str] = n.Fret
+			played = true
+		}
+		for _, r := range strings.TrimLeft(cells(line, m), "0123456789") {
+			if r != '-' && r != '|' {
+				return "", false
+			}
+		}
+	}
+	if !played {
+		return "", false
+	}
+
+	low, high := models.MaxFret, 0
+	for _, fret := range frets {
+		if fret > 0 {
+			low, high = min(low, fret), max(high, fret)
+		}
+	}
+	base := 1
+	if high > 5 {
+		base = low
+	}
+	written := make([]string, 0, len(frets))
+	for str := len(frets) - 1; str >= 0; str-- {
+		switch fret := frets[str]; {
+		case fret < 0:
+			written = append(written, "x")
+		case fret == 0:
+			written = append(written, "0")
+		default:
+			written = append(written, strconv.Itoa(fret-base+1))
+		}
+	}
+	return fmt.Sprintf("{define: %s base-fret %d frets %s}", name, base, strings.Join(written, " ")), true
+}
+
+// writeChordProSheet writes the lines of the sheet.
+func writeChordProSheet(b *strings.Builder, lines []string) {
+	section := "" // the kind of section open
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if section != "" && strings.TrimSpace(line) == "" {
+			fmt.Fprintf(b, "{end_of_%s}\n", section)
+			section = ""
+		}
+
+		if m := sectionLine.FindStringSubmatch(line); m != nil && !chordName.MatchString(m[1]) {
+			if section != "" {
+				fmt.Fprintf(b, "{end_of_%s}\n", section)
+			}
+			section = sectionKind(m[1])
+			if strings.EqualFold(m[1], section) {
+				fmt.Fprintf(b, "{start_of_%s}\n", section)
+			} else {
+				fmt.Fprintf(b, "{start_of_%s: %s}\n", section, m[1])
+			}
+			continue
+		}
+
+		if m := commentLine.FindStringSubmatch(line); m != nil {
+			fmt.Fprintf(b, "{comment: %s}\n", m[1])
+			continue
+		}
+		if isChordLine(line) {
+			lyric := ""
+			if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && !isChordLine(lines[i+1]) &&
+				!sectionLine.MatchString(lines[i+1]) {
+				lyric = lines[i+1]
+				i++
+			}
+			b.WriteString(inlineChords(line, lyric) + "\n")
+			continue
+		}
+		b.WriteString(line + "\n")
+	}
+	if section != "" {
+		fmt.Fprintf(b, "{end_of_%s}\n", section)
+	}
+}
+
+// sectionKind names the section directive for a heading: "Verse 2" opens
+// a verse, "Pre-Chorus" a prechorus.
+func sectionKind(heading string) string {
+	word, _, _ := strings.Cut(strings.ToLower(heading), " ")
+	kind := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		return -1
+	}, word)
+	if kind == "" {
+		return "verse"
+	}
+	return kind
+}
+
+// isChordLine reports whether a line is nothing but chord names.
+func isChordLine(line string) bool {
+	fields := strings.Fields(line)
+	for _, f := range fields {
+		if !chordName.MatchString(f) {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// inlineChords puts each chord of a chord line into the lyric at the
+// column it stands over, padding the lyric with spaces where the chords
+// run past its end.
+func inlineChords(chords, lyric string) string {
+	words := []rune(lyric)
+	type placed struct {
+		column int
+		name   string
+	}
+	var all []placed
+	runes := []rune(chords)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == ' ' {
+			continue
+		}
+		start := i
+		for i < len(runes) && runes[i] != ' ' {
+			i++
+		}
+		all = append(all, placed{start, string(runes[start:i])})
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, c := range all {
+		for pos < c.column {
+			if pos < len(words) {
+				b.WriteRune(words[pos])
+			} else {
+				b.WriteByte(' ')
+			}
+			pos++
+		}
+		b.WriteString("[" + c.name + "]")
+	}
+	if pos < len(words) {
+		b.WriteString(string(words[pos:]))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// blankContent reports whether the lines hold no notes.
+func blankContent(content [6]string) bool {
+	for _, line := range content {
+		if strings.Trim(line, "-|") != "" {
+			return false
+		}
+	}
+	return true
+}
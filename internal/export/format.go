@@ -0,0 +1,123 @@
+// internal/export/format.go
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// Format is a file format tabs can be exported to.
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMusicXML Format = "musicxml"
+	FormatLilyPond Format = "lilypond"
+	FormatSVG      Format = "svg"
+	FormatPDF      Format = "pdf"
+	FormatHTML     Format = "html"
+	FormatChordPro Format = "chordpro"
+)
+
+// Formats lists the export formats in the order the export dialog cycles
+// through them.
+var Formats = []Format{FormatText, FormatMusicXML, FormatLilyPond, FormatSVG, FormatPDF, FormatHTML, FormatChordPro}
+
+// Ext returns the file extension written for the format.
+func (f Format) Ext() string {
+	switch f {
+	case FormatMusicXML:
+		return ".musicxml"
+	case FormatLilyPond:
+		return ".ly"
+	case FormatSVG:
+		return ".svg"
+	case FormatPDF:
+		return ".pdf"
+	case FormatHTML:
+		return ".html"
+	case FormatChordPro:
+		return ".cho"
+	}
+	return ".txt"
+}
+
+// String returns the name of the format for display.
+func (f Format) String() string {
+	switch f {
+	case FormatMusicXML:
+		return "MusicXML"
+	case FormatLilyPond:
+		return "LilyPond"
+	case FormatSVG:
+		return "SVG"
+	case FormatPDF:
+		return "PDF"
+	case FormatHTML:
+		return "HTML"
+	case FormatChordPro:
+		return "ChordPro"
+	}
+	return "plain text"
+}
+
+// ParseFormat reads a format name as given on the command line.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q", name)
+}
+
+// FormatFor picks the format from the extension of path, or returns def if
+// the extension is not one of an export format.
+func FormatFor(path string, def Format) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".musicxml", ".xml":
+		return FormatMusicXML
+	case ".ly":
+		return FormatLilyPond
+	case ".svg":
+		return FormatSVG
+	case ".pdf":
+		return FormatPDF
+	case ".html", ".htm":
+		return FormatHTML
+	case ".cho", ".chordpro", ".chopro", ".crd":
+		return FormatChordPro
+	case ".txt":
+		return FormatText
+	}
+	return def
+}
+
+// Options holds the settings of the formats that have any.
+type Options struct {
+	ASCII    ASCIIOptions
+	LilyPond LilyPondOptions
+}
+
+// Write writes a tab in the given format, with that format's options.
+func Write(w io.Writer, tab *models.Tab, format Format, opts Options) error {
+	switch format {
+	case FormatMusicXML:
+		return WriteMusicXML(w, tab)
+	case FormatLilyPond:
+		return WriteLilyPond(w, tab, opts.LilyPond)
+	case FormatSVG:
+		return WriteSVG(w, tab)
+	case FormatPDF:
+		return WritePDF(w, tab)
+	case FormatHTML:
+		return WriteHTML(w, tab)
+	case FormatChordPro:
+		return WriteChordPro(w, tab)
+	}
+	return WriteASCII(w, tab, opts.ASCII)
+}
@@ -0,0 +1,315 @@
+// internal/export/html.go
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/midi"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// HTML pages are a single file to open in any browser: the tab as text in
+// systems of whole measures, and a script that plays it with the Web Audio
+// API, moving a cursor along the columns as they sound.
+
+// htmlColumns is the width of a system, string names excluded.
+const htmlColumns = 76
+
+// htmlNote is a note to play, in seconds at the tab's tempo.
+type htmlNote struct {
+	Start    float64 `json:"t"`
+	Duration float64 `json:"d"`
+	Pitch    int     `json:"m"`
+}
+
+// htmlSong is the data the playback script reads.
+type htmlSong struct {
+	Sixteenth float64    `json:"sixteenth"` // seconds per column
+	Length    int        `json:"length"`    // columns
+	Columns   [][2]int   `json:"columns"`   // system and column on screen of each tab column
+	Notes     []htmlNote `json:"notes"`
+}
+
+// WriteHTML writes a tab as a standalone web page with the title, artist,
+// tempo, tuning, time signature and notes, and playback of the notes
+// midi.Player plays, with a cursor following along.
+func WriteHTML(w io.Writer, tab *models.Tab) error {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	b.WriteString("<meta name=\"generator\" content=\"tuitar\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n", html.EscapeString(songTitle(tab)), htmlStyle)
+
+	b.WriteString("<header>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(tab.Name))
+	if tab.Artist != "" {
+		fmt.Fprintf(&b, "<p class=\"artist\">%s</p>\n", html.EscapeString(tab.Artist))
+	}
+	fmt.Fprintf(&b, "<p class=\"info\"><span>Tempo %d bpm</span><span>Tuning %s</span><span>%s</span></p>\n",
+		tab.Tempo, html.EscapeString(tab.TuningString()), html.EscapeString(tab.TimeSignature))
+	b.WriteString("<div class=\"controls\">\n")
+	b.WriteString("<button id=\"play\" type=\"button\">Play</button>\n")
+	b.WriteString("<button id=\"stop\" type=\"button\">Stop</button>\n")
+	b.WriteString("<label>Speed <select id=\"speed\">")
+	for _, percent := range []int{50, 75, 100, 125, 150} {
+		selected := ""
+		if percent == 100 {
+			selected = " selected"
+		}
+		fmt.Fprintf(&b, "<option value=\"%d\"%s>%d%%</option>", percent, selected, percent)
+	}
+	b.WriteString("</select></label>\n<span class=\"hint\">Space plays and pauses</span>\n</div>\n</header>\n<main>\n")
+
+	song := htmlSong{Columns: writeHTMLSystems(&b, tab)}
+	song.Length = len(song.Columns)
+
+	tempo := tab.Tempo
+	if tempo <= 0 {
+		tempo = 120 // as midi.TabNotes plays it
+	}
+	song.Sixteenth = 60 / float64(tempo*models.ColumnsPerBeat)
+	for _, n := range midi.TabNotes(tab, tempo, 0) {
+		song.Notes = append(song.Notes, htmlNote{Start: n.Start.Seconds(), Duration: n.Duration.Seconds(), Pitch: n.MidiNote})
+	}
+
+	if notes := strings.TrimSpace(tab.Notes); notes != "" {
+		fmt.Fprintf(&b, "<section class=\"notes\">%s</section>\n", html.EscapeString(notes))
+	}
+	b.WriteString("</main>\n")
+
+	data, err := json.Marshal(song)
+	if err != nil {
+		return err
+	}
+	// json.Marshal escapes <, > and &, so the data cannot close the script
+	fmt.Fprintf(&b, "<script>\nconst song = %s;\n%s</script>\n</body>\n</html>\n", data, htmlScript)
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// writeHTMLSystems writes the tab's systems, each with its measure numbers,
+// string lines and a cursor, and returns where on screen every column of
+// the tab is.
+func writeHTMLSystems(b *strings.Builder, tab *models.Tab) [][2]int {
+	labels := tab.TuningLabels()
+	prefix := len([]rune(labels[0])) + 1
+	top := []rune(tab.Content[0])
+	measures := tab.Measures()
+
+	var columns [][2]int
+	for i, system := range groupSystems(tab, measures, htmlColumns) {
+		var lines [6]strings.Builder
+		numberLine := []rune(strings.Repeat(" ", prefix))
+		for s := range lines {
+			lines[s].WriteString(labels[s] + "|")
+		}
+		width := prefix
+		for _, index := range system {
+			m := measures[index]
+			label := []rune(fmt.Sprint(index + 1))
+			// Skip numbers that would run into the previous one
+			if len(strings.TrimRight(string(numberLine), " "))+1 < width {
+				numberLine = append(numberLine, []rune(strings.Repeat(" ", width-len(numberLine)))...)
+				numberLine = append(numberLine, label...)
+			}
+			for pos := m.Start; pos < m.End; pos++ {
+				columns = append(columns, [2]int{i, width + pos - m.Start})
+			}
+			for s, line := range tab.Content {
+				lines[s].WriteString(cells(line, m))
+			}
+			width += m.Len()
+			if m.End > len(top) || top[m.End-1] != '|' {
+				for s := range lines {
+					lines[s].WriteString("|")
+				}
+				width++
+			}
+		}
+
+		b.WriteString("<div class=\"system\"><pre class=\"numbers\">")
+		b.WriteString(html.EscapeString(strings.TrimRight(string(numberLine), " ")))
+		b.WriteString("</pre><div class=\"staff\"><pre>")
+		for s := range lines {
+			if s > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(html.EscapeString(lines[s].String()))
+		}
+		b.WriteString("</pre><div class=\"cursor\"></div></div></div>\n")
+	}
+	return columns
+}
+
+const htmlStyle = `body {
+  margin: 0 auto;
+  max-width: 60rem;
+  padding: 1.5rem;
+  font-family: Helvetica, Arial, sans-serif;
+  color: #222;
+  background: #fdfcf8;
+}
+h1 { margin: 0 0 0.25rem; font-size: 1.8rem; }
+.artist { margin: 0 0 0.5rem; font-size: 1.1rem; color: #555; }
+.info span { margin-right: 1.5rem; color: #555; }
+header {
+  position: sticky;
+  top: 0;
+  padding: 0.5rem 0;
+  background: #fdfcf8;
+  border-bottom: 1px solid #ddd;
+  z-index: 1;
+}
+.controls { display: flex; gap: 0.75rem; align-items: center; }
+.controls button { min-width: 5rem; padding: 0.3rem 0.8rem; font-size: 1rem; }
+.hint { color: #888; font-size: 0.85rem; }
+.system { margin: 1.25rem 0; overflow-x: auto; }
+pre {
+  margin: 0;
+  font-family: "DejaVu Sans Mono", Menlo, Consolas, monospace;
+  font-size: 15px;
+  line-height: 1.3;
+}
+.numbers { color: #999; font-size: 11px; line-height: 1.2; padding-left: 0; }
+.staff { position: relative; width: max-content; }
+.cursor {
+  display: none;
+  position: absolute;
+  top: 0;
+  width: 1ch;
+  height: 100%;
+  font-family: "DejaVu Sans Mono", Menlo, Consolas, monospace;
+  font-size: 15px;
+  background: rgba(230, 120, 30, 0.35);
+  border-radius: 2px;
+  pointer-events: none;
+}
+.notes { margin-top: 2rem; white-space: pre-wrap; line-height: 1.5; }
+`
+
+const htmlScript = `(() => {
+  const playButton = document.getElementById("play");
+  const speedSelect = document.getElementById("speed");
+  const cursors = Array.from(document.querySelectorAll(".cursor"));
+  let audio = null;
+  let playing = false;
+  let position = 0;  // seconds into the tab at the tab's tempo
+  let started = 0;   // audio clock when position was last set
+  let next = 0;      // index of the next note to schedule
+  let shown = null;
+  let sounding = new Set();
+  let rate = 1;      // the speed playback started at
+
+  const now = () => playing ? position + (audio.currentTime - started) * rate : position;
+
+  // A plucked string: a triangle wave dying away
+  function pluck(note, when) {
+    const length = Math.max(note.d * 4, 0.25) / rate;
+    const osc = audio.createOscillator();
+    const gain = audio.createGain();
+    osc.type = "triangle";
+    osc.frequency.value = 440 * Math.pow(2, (note.m - 69) / 12);
+    gain.gain.setValueAtTime(0.0001, when);
+    gain.gain.exponentialRampToValueAtTime(0.25, when + 0.005);
+    gain.gain.exponentialRampToValueAtTime(0.0001, when + length);
+    osc.connect(gain).connect(audio.destination);
+    osc.start(when);
+    osc.stop(when + length + 0.05);
+    sounding.add(osc);
+    osc.onended = () => sounding.delete(osc);
+  }
+
+  function show(column) {
+    const at = song.columns[column];
+    if (shown !== null && (!at || shown !== at[0])) {
+      cursors[shown].style.display = "none";
+    }
+    if (!at) {
+      shown = null;
+      return;
+    }
+    const cursor = cursors[at[0]];
+    cursor.style.left = at[1] + "ch";
+    if (shown !== at[0]) {
+      cursor.style.display = "block";
+      cursor.scrollIntoView({ block: "center", behavior: "smooth" });
+      shown = at[0];
+    }
+  }
+
+  function tick() {
+    if (!playing) {
+      return;
+    }
+    const t = now();
+    if (t >= song.length * song.sixteenth) {
+      stop();
+      return;
+    }
+    // Schedule the notes of the next fifth of a second
+    while (next < song.notes.length && song.notes[next].t < t + 0.2 * rate) {
+      const note = song.notes[next++];
+      pluck(note, Math.max(audio.currentTime, started + (note.t - position) / rate));
+    }
+    show(Math.floor(t / song.sixteenth));
+    requestAnimationFrame(tick);
+  }
+
+  function play() {
+    audio = audio || new AudioContext();
+    audio.resume();
+    started = audio.currentTime;
+    rate = Number(speedSelect.value) / 100;
+    next = song.notes.findIndex(n => n.t >= position - 1e-6);
+    if (next < 0) {
+      next = song.notes.length;
+    }
+    playing = true;
+    playButton.textContent = "Pause";
+    tick();
+  }
+
+  // silence stops the notes scheduled but not yet played out
+  function silence() {
+    sounding.forEach(osc => osc.stop());
+    sounding.clear();
+  }
+
+  function pause() {
+    position = now();
+    playing = false;
+    silence();
+    playButton.textContent = "Play";
+  }
+
+  function stop() {
+    if (playing) {
+      silence();
+    }
+    playing = false;
+    position = 0;
+    playButton.textContent = "Play";
+    show(-1);
+  }
+
+  playButton.addEventListener("click", () => playing ? pause() : play());
+  document.getElementById("stop").addEventListener("click", stop);
+  speedSelect.addEventListener("change", () => {
+    if (playing) {
+      pause();
+      play();
+    }
+  });
+  document.addEventListener("keydown", e => {
+    if (e.code === "Space" && e.target.tagName !== "SELECT" && e.target.tagName !== "BUTTON") {
+      e.preventDefault();
+      playing ? pause() : play();
+    }
+  });
+})();
+`
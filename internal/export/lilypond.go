@@ -0,0 +1,297 @@
+// internal/export/lilypond.go
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// LilyPond scores for printing with lilypond. The music is written once and
+// shared by the tab staff and the optional notation staff above it.
+
+// LilyPondOptions controls what a LilyPond score prints.
+type LilyPondOptions struct {
+	Notation bool `json:"notation"` // a standard notation staff above the tab
+}
+
+// lilyDurations names the note lengths noteLengths produces.
+var lilyDurations = map[int]string{
+	16: "1", 12: "2.", 8: "2", 6: "4.", 4: "4", 3: "8.", 2: "8", 1: "16",
+}
+
+// lilyNames spells the twelve pitch classes in LilyPond's default
+// (Dutch) note names.
+var lilyNames = [12]string{"c", "cis", "d", "dis", "e", "f", "fis", "g", "gis", "a", "ais", "b"}
+
+var (
+	capoLine = regexp.MustCompile(`^Capo (\d+)$`)
+	barLine  = regexp.MustCompile(`^Bar (\d+): (.+)$`)
+	// noteLabel matches the "Label: value" lines importers write to the
+	// notes, such as "Key: G" and "Comment: text".
+	noteLabel = regexp.MustCompile(`^[A-Z][A-Za-z ]*: `)
+)
+
+// WriteLilyPond writes a tab as a LilyPond score: a tab staff tuned like
+// the tab, with durations, bar lines, repeats, the time signature and
+// tempo, and hammer-ons, pull-offs, slides, bends, harmonics, ghost and
+// dead notes. Notes lines of the form "Capo N" and "Bar N: text" become a
+// subtitle and marks, and a lyric block of the notes is set under the top
+// staff.
+func WriteLilyPond(w io.Writer, tab *models.Tab, opts LilyPondOptions) error {
+	var b strings.Builder
+	capo, marks, lyrics := splitNotes(tab.Notes)
+
+	b.WriteString("\\version \"2.24.0\"\n\n\\header {\n")
+	fmt.Fprintf(&b, "  title = %s\n", lilyString(tab.Name))
+	if tab.Artist != "" {
+		fmt.Fprintf(&b, "  composer = %s\n", lilyString(tab.Artist))
+	}
+	if capo > 0 {
+		fmt.Fprintf(&b, "  subtitle = %s\n", lilyString(fmt.Sprintf("Capo %d", capo)))
+	}
+	b.WriteString("  tagline = ##f\n}\n\n")
+
+	beats, unit := models.ParseTimeSignature(tab.TimeSignature)
+	fmt.Fprintf(&b, "music = {\n  \\time %d/%d\n", beats, unit)
+	if tab.Tempo > 0 {
+		fmt.Fprintf(&b, "  \\tempo 4 = %d\n", tab.Tempo)
+	}
+	writeLilyMeasures(&b, scoreMeasures(tab), tab.ColumnsPerMeasure(), marks)
+	b.WriteString("  \\bar \"|.\"\n}\n")
+
+	if len(lyrics) > 0 {
+		b.WriteString("\nwords = \\lyricmode {\n")
+		for _, line := range models.WrapWords(lyrics, 70) {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	var tuning []string
+	open := tab.OpenStrings()
+	for s := len(open) - 1; s >= 0; s-- {
+		tuning = append(tuning, lilyPitch(open[s]))
+	}
+
+	b.WriteString("\n\\score {\n  <<\n")
+	if opts.Notation {
+		b.WriteString("    \\new Staff \\with { \\omit StringNumber } {\n")
+		b.WriteString("      \\clef \"treble_8\"\n      \\new Voice = \"guitar\" \\music\n    }\n")
+	}
+	if len(lyrics) > 0 && opts.Notation {
+		b.WriteString("    \\new Lyrics \\lyricsto \"guitar\" \\words\n")
+	}
+	fmt.Fprintf(&b, "    \\new TabStaff \\with { stringTunings = \\stringTuning <%s> } {\n", strings.Join(tuning, " "))
+	if opts.Notation {
+		b.WriteString("      \\music\n    }\n")
+	} else {
+		b.WriteString("      \\tabFullNotation\n      \\new TabVoice = \"guitar\" \\music\n    }\n")
+	}
+	if len(lyrics) > 0 && !opts.Notation {
+		b.WriteString("    \\new Lyrics \\lyricsto \"guitar\" \\words\n")
+	}
+	b.WriteString("  >>\n  \\layout { }\n}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeLilyMeasures writes the measures one to a line, each ending in a bar
+// check. A measure shorter or longer than the time signature's sets the
+// measure length until a measure of another length. Repeats that are
+// opened and never closed run to the end, and ones closed without being
+// opened start at the beginning.
+func writeLilyMeasures(b *strings.Builder, measures []scoreMeasure, perMeasure int, marks map[int][]string) {
+	repeating := false
+	for _, m := range measures {
+		if m.RepeatEnd && !m.RepeatStart {
+			repeating = true
+			break
+		}
+		if m.RepeatStart {
+			break
+		}
+	}
+	if repeating {
+		b.WriteString("  \\repeat volta 2 {\n")
+	}
+
+	indent := func() string {
+		if repeating {
+			return "    "
+		}
+		return "  "
+	}
+	slur := false // a hammer-on or pull-off is waiting for its next note
+	length := perMeasure
+	for i, m := range measures {
+		if m.RepeatStart {
+			if repeating {
+				b.WriteString("  }\n")
+			}
+			b.WriteString("  \\repeat volta 2 {\n")
+			repeating = true
+		}
+		if m.Length != length {
+			fmt.Fprintf(b, "%s\\set Timing.measureLength = #(ly:make-moment %d/16)\n", indent(), m.Length)
+			length = m.Length
+		}
+		for _, text := range marks[i+1] {
+			fmt.Fprintf(b, "%s\\mark %s\n", indent(), lilyString(text))
+		}
+
+		b.WriteString(indent())
+		for _, event := range m.Events {
+			b.WriteString(lilyEvent(event, &slur) + " ")
+		}
+		b.WriteString("|\n")
+
+		if m.RepeatEnd && repeating {
+			b.WriteString("  }\n")
+			repeating = false
+		}
+	}
+	if repeating {
+		b.WriteString("  }\n")
+	}
+}
+
+// lilyEvent writes an event as a chord, or a rest, split into tied notes
+// when its length is not a single note value.
+func lilyEvent(event scoreEvent, slur *bool) string {
+	parts := noteLengths(event.Length)
+	if len(event.Notes) == 0 {
+		rests := make([]string, len(parts))
+		for i, length := range parts {
+			rests[i] = "r" + lilyDurations[length]
+		}
+		return strings.Join(rests, " ")
+	}
+
+	var notes []string
+	var after string // articulations on the first of the tied notes
+	hammer := false
+	for _, sn := range event.Notes {
+		note := lilyPitch(sn.Pitch)
+		switch {
+		case sn.Dead:
+			note = "\\deadNote " + note
+		case sn.Ghost:
+			note = "\\parenthesize " + note
+		}
+		if sn.Harmonic {
+			note += "\\harmonic"
+		}
+		notes = append(notes, note+"\\"+strconv.Itoa(sn.String+1))
+
+		switch sn.Legato {
+		case 'h', 'p':
+			hammer = true
+		case '/', '\\':
+			if !strings.Contains(after, "\\glissando") {
+				after += "\\glissando"
+			}
+		}
+		if sn.Bend != 0 && !strings.Contains(after, "\\bendAfter") {
+			after += fmt.Sprintf("-\\bendAfter #%+d", sn.Bend)
+		}
+		if sn.Vibrato && !strings.Contains(after, "vib.") {
+			after += "^\"vib.\""
+		}
+	}
+	if *slur {
+		after = ")" + after
+		*slur = false
+	}
+	if hammer {
+		after += "("
+		*slur = true
+	}
+
+	chord := "<" + strings.Join(notes, " ") + ">"
+	var out []string
+	for i, length := range parts {
+		text := chord + lilyDurations[length]
+		if i == 0 {
+			text += after
+		}
+		if i < len(parts)-1 {
+			text += "~"
+		}
+		out = append(out, text)
+	}
+	return strings.Join(out, " ")
+}
+
+// lilyPitch spells a MIDI note with LilyPond's octave marks, where c is
+// the C below middle C.
+func lilyPitch(midi int) string {
+	name := lilyNames[(midi%12+12)%12]
+	octave := midi/12 - 4
+	if octave > 0 {
+		return name + strings.Repeat("'", octave)
+	}
+	return name + strings.Repeat(",", -octave)
+}
+
+// lilyString quotes text as a LilyPond string.
+func lilyString(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	return "\"" + strings.ReplaceAll(text, "\"", "\\\"") + "\""
+}
+
+// splitNotes reads the conventions of a tab's notes: a "Capo N" line, and
+// "Bar N: text" lines marking a measure. The lyrics are the paragraphs of
+// nothing but words, the way importers write a song's lyrics; paragraphs
+// with "Label: value" lines, "[Section]" headings or lines of chord names
+// are a song sheet or remarks and are left out. Each word is split into
+// syllables at hyphens and quoted so that LilyPond reads each as one.
+func splitNotes(notes string) (capo int, marks map[int][]string, lyrics []string) {
+	marks = make(map[int][]string)
+	var block []string // lyric lines of the paragraph so far
+	words := true      // whether the paragraph is all lyric lines
+	endBlock := func() {
+		if words {
+			for _, word := range strings.Fields(strings.Join(block, " ")) {
+				var syllables []string
+				for _, syllable := range strings.Split(word, "-") {
+					if syllable != "" {
+						syllables = append(syllables, lilyString(syllable))
+					}
+				}
+				if len(syllables) > 0 {
+					lyrics = append(lyrics, strings.Join(syllables, " -- "))
+				}
+			}
+		}
+		block, words = nil, true
+	}
+
+	for _, line := range strings.Split(notes, "\n") {
+		line = strings.TrimSpace(line)
+		if m := capoLine.FindStringSubmatch(line); m != nil {
+			capo, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if m := barLine.FindStringSubmatch(line); m != nil {
+			bar, _ := strconv.Atoi(m[1])
+			marks[bar] = append(marks[bar], m[2])
+			continue
+		}
+		switch {
+		case line == "":
+			endBlock()
+		case noteLabel.MatchString(line), sectionLine.MatchString(line), isChordLine(line):
+			words = false
+		default:
+			block = append(block, line)
+		}
+	}
+	endBlock()
+	return capo, marks, lyrics
+}
@@ -0,0 +1,343 @@
+// internal/export/musicxml.go
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// MusicXML 4.0 partwise scores, with the tab on a single TAB staff. One
+// division is one tab column, a sixteenth note.
+
+const musicXMLDoctype = `<!DOCTYPE score-partwise PUBLIC "-//Recordare//DTD MusicXML 4.0 Partwise//EN" "http://www.musicxml.org/dtds/partwise.dtd">` + "\n"
+
+// noteTypes names the note lengths noteLengths produces.
+var noteTypes = map[int]struct {
+	name string
+	dot  bool
+}{
+	16: {"whole", false}, 12: {"half", true}, 8: {"half", false}, 6: {"quarter", true},
+	4: {"quarter", false}, 3: {"eighth", true}, 2: {"eighth", false}, 1: {"16th", false},
+}
+
+type mxScore struct {
+	XMLName        xml.Name         `xml:"score-partwise"`
+	Version        string           `xml:"version,attr"`
+	Work           mxWork           `xml:"work"`
+	Identification mxIdentification `xml:"identification"`
+	PartList       mxPartList       `xml:"part-list"`
+	Part           mxPart           `xml:"part"`
+}
+
+type mxWork struct {
+	Title string `xml:"work-title"`
+}
+
+type mxIdentification struct {
+	Creator  *mxCreator `xml:"creator"`
+	Software string     `xml:"encoding>software"`
+	Date     string     `xml:"encoding>encoding-date"`
+}
+
+type mxCreator struct {
+	Type string `xml:"type,attr"`
+	Name string `xml:",chardata"`
+}
+
+type mxPartList struct {
+	Part mxScorePart `xml:"score-part"`
+}
+
+type mxScorePart struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"part-name"`
+}
+
+type mxPart struct {
+	ID       string      `xml:"id,attr"`
+	Measures []mxMeasure `xml:"measure"`
+}
+
+type mxMeasure struct {
+	Number     string        `xml:"number,attr"`
+	Attributes *mxAttributes `xml:"attributes"`
+	Direction  *mxDirection  `xml:"direction"`
+	Notes      []mxNote      `xml:"note"`
+	Barline    *mxBarline    `xml:"barline"`
+}
+
+type mxAttributes struct {
+	Divisions    int            `xml:"divisions"`
+	Fifths       int            `xml:"key>fifths"`
+	Beats        int            `xml:"time>beats"`
+	BeatType     int            `xml:"time>beat-type"`
+	ClefSign     string         `xml:"clef>sign"`
+	ClefLine     int            `xml:"clef>line"`
+	StaffDetails mxStaffDetails `xml:"staff-details"`
+}
+
+type mxStaffDetails struct {
+	Lines  int             `xml:"staff-lines"`
+	Tuning []mxStaffTuning `xml:"staff-tuning"`
+}
+
+type mxStaffTuning struct {
+	Line   int    `xml:"line,attr"`
+	Step   string `xml:"tuning-step"`
+	Alter  int    `xml:"tuning-alter,omitempty"`
+	Octave int    `xml:"tuning-octave"`
+}
+
+type mxDirection struct {
+	Placement string `xml:"placement,attr"`
+	BeatUnit  string `xml:"direction-type>metronome>beat-unit"`
+	PerMinute int    `xml:"direction-type>metronome>per-minute"`
+	Sound     struct {
+		Tempo int `xml:"tempo,attr"`
+	} `xml:"sound"`
+}
+
+type mxNote struct {
+	Chord     *struct{}    `xml:"chord"`
+	Pitch     *mxPitch     `xml:"pitch"`
+	Rest      *struct{}    `xml:"rest"`
+	Duration  int          `xml:"duration"`
+	Ties      []mxTie      `xml:"tie"`
+	Voice     string       `xml:"voice"`
+	Type      string       `xml:"type"`
+	Dot       *struct{}    `xml:"dot"`
+	Notehead  *mxNotehead  `xml:"notehead"`
+	Notations *mxNotations `xml:"notations"`
+}
+
+type mxPitch struct {
+	Step   string `xml:"step"`
+	Alter  int    `xml:"alter,omitempty"`
+	Octave int    `xml:"octave"`
+}
+
+type mxTie struct {
+	Type string `xml:"type,attr"`
+}
+
+type mxNotehead struct {
+	Parentheses string `xml:"parentheses,attr,omitempty"`
+	Value       string `xml:",chardata"`
+}
+
+type mxNotations struct {
+	Tied      []mxTie      `xml:"tied"`
+	Slides    []mxLine     `xml:"slide"`
+	Technical *mxTechnical `xml:"technical"`
+}
+
+type mxLine struct {
+	Type   string `xml:"type,attr"`
+	Number int    `xml:"number,attr"`
+	Text   string `xml:",chardata"`
+}
+
+type mxTechnical struct {
+	HammerOns []mxLine    `xml:"hammer-on"`
+	PullOffs  []mxLine    `xml:"pull-off"`
+	Bend      *mxBend     `xml:"bend"`
+	Harmonic  *mxHarmonic `xml:"harmonic"`
+	String    int         `xml:"string"`
+	Fret      int         `xml:"fret"`
+}
+
+type mxBend struct {
+	Alter int `xml:"bend-alter"`
+}
+
+type mxHarmonic struct {
+	Natural struct{} `xml:"natural"`
+}
+
+type mxBarline struct {
+	Location string `xml:"location,attr"`
+	Style    string `xml:"bar-style"`
+}
+
+// WriteMusicXML writes a tab as a MusicXML 4.0 score for notation
+// software: one part on a six-line TAB staff tuned like the tab, with the
+// string and fret of every note, the time signature and tempo, and
+// hammer-ons, pull-offs, slides, bends, harmonics, ghost and dead notes.
+func WriteMusicXML(w io.Writer, tab *models.Tab) error {
+	score := mxScore{
+		Version: "4.0",
+		Work:    mxWork{Title: tab.Name},
+		Identification: mxIdentification{
+			Software: "tuitar",
+			Date:     tab.UpdatedAt.Format("2006-01-02"),
+		},
+		PartList: mxPartList{Part: mxScorePart{ID: "P1", Name: "Guitar"}},
+		Part:     mxPart{ID: "P1"},
+	}
+	if tab.Artist != "" {
+		score.Identification.Creator = &mxCreator{Type: "composer", Name: tab.Artist}
+	}
+
+	pending := make(map[int]rune) // legato waiting for the next note on a string
+	measures := scoreMeasures(tab)
+	for i, measure := range measures {
+		m := mxMeasure{Number: strconv.Itoa(i + 1)}
+		if i == 0 {
+			m.Attributes = musicXMLAttributes(tab)
+			m.Direction = &mxDirection{Placement: "above", BeatUnit: "quarter", PerMinute: tab.Tempo}
+			m.Direction.Sound.Tempo = tab.Tempo
+		}
+		for _, event := range measure.Events {
+			m.Notes = append(m.Notes, musicXMLNotes(event, pending)...)
+		}
+		if i == len(measures)-1 {
+			m.Barline = &mxBarline{Location: "right", Style: "light-heavy"}
+		}
+		score.Part.Measures = append(score.Part.Measures, m)
+	}
+
+	if _, err := io.WriteString(w, xml.Header+musicXMLDoctype); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(score); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func musicXMLAttributes(tab *models.Tab) *mxAttributes {
+	beats, unit := models.ParseTimeSignature(tab.TimeSignature)
+	attrs := &mxAttributes{
+		Divisions: models.ColumnsPerBeat,
+		Beats:     beats,
+		BeatType:  unit,
+		ClefSign:  "TAB",
+		ClefLine:  5,
+	}
+
+	attrs.StaffDetails.Lines = len(tab.Content)
+	open := tab.OpenStrings()
+	for line := 1; line <= len(open); line++ {
+		step, alter, octave := musicXMLPitch(open[len(open)-line])
+		attrs.StaffDetails.Tuning = append(attrs.StaffDetails.Tuning,
+			mxStaffTuning{Line: line, Step: step, Alter: alter, Octave: octave})
+	}
+	return attrs
+}
+
+// musicXMLNotes writes an event as a chord, split into tied notes when its
+// length is not a single note value. pending carries hammer-ons, pull-offs
+// and slides over to the next note on the same string.
+func musicXMLNotes(event scoreEvent, pending map[int]rune) []mxNote {
+	var notes []mxNote
+	parts := noteLengths(event.Length)
+	for p, length := range parts {
+		kind := noteTypes[length]
+
+		if len(event.Notes) == 0 {
+			note := mxNote{Rest: &struct{}{}, Duration: length, Voice: "1", Type: kind.name}
+			if kind.dot {
+				note.Dot = &struct{}{}
+			}
+			notes = append(notes, note)
+			continue
+		}
+
+		for c, sn := range event.Notes {
+			step, alter, octave := musicXMLPitch(sn.Pitch)
+			note := mxNote{
+				Pitch:    &mxPitch{Step: step, Alter: alter, Octave: octave},
+				Duration: length,
+				Voice:    "1",
+				Type:     kind.name,
+			}
+			if c > 0 {
+				note.Chord = &struct{}{}
+			}
+			if kind.dot {
+				note.Dot = &struct{}{}
+			}
+			switch {
+			case sn.Dead:
+				note.Notehead = &mxNotehead{Value: "x"}
+			case sn.Ghost:
+				note.Notehead = &mxNotehead{Parentheses: "yes", Value: "normal"}
+			}
+
+			notations := &mxNotations{Technical: &mxTechnical{String: sn.String + 1, Fret: sn.Fret}}
+			if p > 0 {
+				note.Ties = append(note.Ties, mxTie{Type: "stop"})
+				notations.Tied = append(notations.Tied, mxTie{Type: "stop"})
+			}
+			if p < len(parts)-1 {
+				note.Ties = append(note.Ties, mxTie{Type: "start"})
+				notations.Tied = append(notations.Tied, mxTie{Type: "start"})
+			}
+			if p == 0 {
+				musicXMLTechniques(sn, notations, pending)
+			}
+			note.Notations = notations
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// musicXMLTechniques adds a note's own techniques, and ends the hammer-on,
+// pull-off or slide that led to it.
+func musicXMLTechniques(sn scoreNote, notations *mxNotations, pending map[int]rune) {
+	tech := notations.Technical
+	number := sn.String + 1
+
+	legato := func(r rune, typ string) {
+		switch r {
+		case 'h':
+			tech.HammerOns = append(tech.HammerOns, mxLine{Type: typ, Number: number, Text: textIf(typ, "H")})
+		case 'p':
+			tech.PullOffs = append(tech.PullOffs, mxLine{Type: typ, Number: number, Text: textIf(typ, "P")})
+		case '/', '\\':
+			notations.Slides = append(notations.Slides, mxLine{Type: typ, Number: number})
+		}
+	}
+	if r, ok := pending[sn.String]; ok {
+		legato(r, "stop")
+		delete(pending, sn.String)
+	}
+	if sn.Legato != 0 {
+		legato(sn.Legato, "start")
+		pending[sn.String] = sn.Legato
+	}
+
+	if sn.Bend != 0 {
+		tech.Bend = &mxBend{Alter: sn.Bend}
+	}
+	if sn.Harmonic {
+		tech.Harmonic = &mxHarmonic{}
+	}
+}
+
+// textIf returns text on the start of a hammer-on or pull-off, where
+// notation software prints it.
+func textIf(typ, text string) string {
+	if typ == "start" {
+		return text
+	}
+	return ""
+}
+
+// musicXMLPitch spells a MIDI note as a step, a sharp if needed, and an
+// octave.
+func musicXMLPitch(midi int) (step string, alter, octave int) {
+	name, octave := models.PitchName(midi)
+	if sharp := strings.TrimSuffix(name, "#"); sharp != name {
+		return sharp, 1, octave
+	}
+	return name, 0, octave
+}
@@ -0,0 +1,197 @@
+// internal/export/musicxml_test.go
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenRiff is a tab with a chord, a hammer-on, a bend, a slide, and dead,
+// ghost and harmonic notes, in drop D.
+func goldenRiff() *models.Tab {
+	tab := models.NewEmptyTab("Golden Riff")
+	tab.Artist = "Test Band"
+	tab.Tempo = 104
+	tab.Tuning = [6]string{"e", "B", "G", "D", "A", "D"}
+	tab.UpdatedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tab.Content = [6]string{
+		"----------------|----------------|",
+		"--------8b------|----------------|",
+		"----5h7---------|--------(7)-----|",
+		"------------7/9-|----------------|",
+		"0---------------|--x-----<12>----|",
+		"0---------------|0---------------|",
+	}
+	return tab
+}
+
+func TestWriteMusicXML(t *testing.T) {
+	tab := goldenRiff()
+
+	var buf bytes.Buffer
+	if err := WriteMusicXML(&buf, tab); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	golden := filepath.Join("testdata", "riff.musicxml")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s; run go test -update if the change is intended\n%s", golden, got)
+	}
+
+	// What notation software needs to read the score as tab, with the
+	// indentation taken out
+	var compact strings.Builder
+	for _, line := range strings.Split(got, "\n") {
+		compact.WriteString(strings.TrimSpace(line))
+	}
+	for _, part := range []string{
+		`<staff-lines>6</staff-lines><staff-tuning line="1"><tuning-step>D</tuning-step><tuning-octave>2</tuning-octave></staff-tuning>`,
+		`<staff-tuning line="6"><tuning-step>E</tuning-step><tuning-octave>4</tuning-octave></staff-tuning>`,
+		`<technical><hammer-on type="start" number="3">H</hammer-on><string>3</string><fret>5</fret></technical>`,
+		`<technical><hammer-on type="stop" number="3"></hammer-on><string>3</string><fret>7</fret></technical>`,
+		`<slide type="start" number="4"></slide><technical><string>4</string><fret>7</fret></technical>`,
+		`<slide type="stop" number="4"></slide><technical><string>4</string><fret>9</fret></technical>`,
+		`<bend><bend-alter>2</bend-alter></bend><string>2</string><fret>8</fret>`,
+		`<per-minute>104</per-minute></metronome></direction-type><sound tempo="104"></sound>`,
+	} {
+		if !strings.Contains(compact.String(), part) {
+			t.Errorf("output lacks %s", part)
+		}
+	}
+}
+
+// musicXMLSequences lists, for the elements WriteMusicXML writes whose
+// content the MusicXML 4.0 schema gives as a sequence, the children in the
+// order the schema allows them. Names on one entry share a place in the
+// sequence; any of them may repeat there.
+var musicXMLSequences = map[string][]string{
+	"score-partwise": {"work", "movement-number", "movement-title", "identification", "defaults", "credit", "part-list", "part"},
+	"work":           {"work-number", "work-title", "opus"},
+	"identification": {"creator", "rights", "encoding", "source", "relation", "miscellaneous"},
+	"score-part":     {"identification", "part-link", "part-name", "part-name-display", "part-abbreviation"},
+	"attributes": {"footnote", "level", "divisions", "key", "time", "staves", "part-symbol", "instruments",
+		"clef", "staff-details", "transpose for-part", "directive", "measure-style"},
+	"key":           {"cancel", "fifths", "mode", "key-octave"},
+	"time":          {"beats", "beat-type", "interchangeable"},
+	"clef":          {"sign", "line", "clef-octave-change"},
+	"staff-details": {"staff-type", "staff-lines", "line-detail", "staff-tuning", "capo", "staff-size"},
+	"staff-tuning":  {"tuning-step", "tuning-alter", "tuning-octave"},
+	"direction":     {"direction-type", "offset", "footnote", "level", "voice", "staff", "sound", "listening"},
+	"metronome":     {"beat-unit", "beat-unit-dot", "per-minute"},
+	"note": {"grace", "cue", "chord", "pitch unpitched rest", "duration", "tie", "instrument", "footnote", "level",
+		"voice", "type", "dot", "accidental", "time-modification", "stem", "notehead", "notehead-text", "staff",
+		"beam", "notations", "lyric", "play", "listen"},
+	"pitch":   {"step", "alter", "octave"},
+	"bend":    {"bend-alter", "pre-bend release", "with-bar"},
+	"barline": {"bar-style", "footnote", "level", "wavy-line", "segno", "coda", "fermata", "ending", "repeat"},
+}
+
+// TestWriteMusicXMLOrder checks the children of each element against the
+// schema's sequences, which a golden file written by the code under test
+// cannot catch.
+func TestWriteMusicXMLOrder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMusicXML(&buf, goldenRiff()); err != nil {
+		t.Fatal(err)
+	}
+
+	type open struct {
+		name  string
+		place int // of the last child in the parent's sequence
+	}
+	var stack []open
+	dec := xml.NewDecoder(&buf)
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			name := tok.Name.Local
+			if n := len(stack); n > 0 {
+				parent := &stack[n-1]
+				if sequence, ok := musicXMLSequences[parent.name]; ok {
+					place := sequencePlace(sequence, name)
+					switch {
+					case place < 0:
+						t.Errorf("<%s> may not hold <%s>", parent.name, name)
+					case place < parent.place:
+						t.Errorf("<%s> comes too late in <%s>; the schema puts it before <%s>", name, parent.name, sequence[parent.place])
+					default:
+						parent.place = place
+					}
+				}
+			}
+			stack = append(stack, open{name: name})
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+func sequencePlace(sequence []string, name string) int {
+	for i, names := range sequence {
+		for _, n := range strings.Fields(names) {
+			if n == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// TestWriteMusicXMLSchema validates the output against the MusicXML 4.0
+// schema with xmllint. The schema is not shipped with tuitar: copy
+// musicxml.xsd, and the xlink.xsd and xml.xsd it imports, from
+// https://github.com/w3c/musicxml/tree/v4.0/schema into testdata to run it.
+func TestWriteMusicXMLSchema(t *testing.T) {
+	schema := filepath.Join("testdata", "musicxml.xsd")
+	if _, err := os.Stat(schema); err != nil {
+		t.Skip("no testdata/musicxml.xsd")
+	}
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed")
+	}
+
+	path := filepath.Join(t.TempDir(), "riff.musicxml")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteMusicXML(f, goldenRiff()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if out, err := exec.Command(xmllint, "--noout", "--nonet", "--schema", schema, path).CombinedOutput(); err != nil {
+		t.Errorf("xmllint: %v\n%s", err, out)
+	}
+}
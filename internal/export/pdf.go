@@ -0,0 +1,152 @@
+// internal/export/pdf.go
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// PDF files are written by hand: a page tree of A4 pages whose content
+// streams draw the marks of renderTab in Helvetica, one of the standard
+// fonts every PDF reader has, so nothing needs embedding.
+
+// pdfWriter numbers objects as they are written and remembers where each
+// starts for the cross-reference table.
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+// object writes object number len(offsets)+1 and returns its number.
+func (p *pdfWriter) object(body string) int {
+	p.offsets = append(p.offsets, p.buf.Len())
+	fmt.Fprintf(&p.buf, "%d 0 obj\n%s\nendobj\n", len(p.offsets), body)
+	return len(p.offsets)
+}
+
+// stream writes a compressed stream object.
+func (p *pdfWriter) stream(data []byte) int {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(data)
+	zw.Close()
+	return p.object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.Bytes()))
+}
+
+// WritePDF draws a tab on as many A4 pages as it takes, laid out as by
+// renderTab.
+func WritePDF(w io.Writer, tab *models.Tab) error {
+	r := renderTab(tab, pageHeight)
+	p := &pdfWriter{}
+	p.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// The catalog and page tree come first; the page tree's kids are only
+	// known once the pages are written, so its number is reserved and it
+	// is written last.
+	p.object("<< /Type /Catalog /Pages 2 0 R >>")
+	p.offsets = append(p.offsets, 0)
+	regular := p.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	bold := p.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	info := fmt.Sprintf("<< /Title %s /Producer (tuitar)", pdfString(tab.Name))
+	if tab.Artist != "" {
+		info += " /Author " + pdfString(tab.Artist)
+	}
+	infoRef := p.object(info + " >>")
+
+	var kids []string
+	for _, page := range r.pages {
+		content := p.stream(pdfContent(page, r.height))
+		ref := p.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+			num(r.width), num(r.height), regular, bold, content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", ref))
+	}
+
+	p.offsets[1] = p.buf.Len()
+	fmt.Fprintf(&p.buf, "2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(kids))
+
+	xref := p.buf.Len()
+	fmt.Fprintf(&p.buf, "xref\n0 %d\n0000000000 65535 f \n", len(p.offsets)+1)
+	for _, offset := range p.offsets {
+		fmt.Fprintf(&p.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&p.buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(p.offsets)+1, infoRef, xref)
+
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// pdfContent draws a page's marks. PDF measures up from the bottom of the
+// page, so every y is flipped.
+func pdfContent(page *renderedPage, height float64) []byte {
+	var b bytes.Buffer
+	b.WriteString("1 J 1 j\n")
+	flip := func(y float64) string { return num(height - y) }
+
+	for _, m := range page.marks {
+		switch m.kind {
+		case markLine:
+			fmt.Fprintf(&b, "%s w %s %s m %s %s l S\n", num(m.width), num(m.x1), flip(m.y1), num(m.x2), flip(m.y2))
+		case markCurve:
+			// The quadratic curve as the cubic PDF draws
+			c1x, c1y := m.x1+2*(m.cx-m.x1)/3, m.y1+2*(m.cy-m.y1)/3
+			c2x, c2y := m.x2+2*(m.cx-m.x2)/3, m.y2+2*(m.cy-m.y2)/3
+			fmt.Fprintf(&b, "%s w %s %s m %s %s %s %s %s %s c S\n", num(m.width), num(m.x1), flip(m.y1),
+				num(c1x), flip(c1y), num(c2x), flip(c2y), num(m.x2), flip(m.y2))
+		case markBlank:
+			fmt.Fprintf(&b, "1 g %s %s %s %s re f 0 g\n", num(m.x1), flip(m.y2), num(m.x2-m.x1), num(m.y2-m.y1))
+		case markDot:
+			// Four quarter circles; k places the control points
+			const k = 0.5523
+			x, y, r := m.x1, m.y1, m.width
+			fmt.Fprintf(&b, "%s %s m", num(x+r), flip(y))
+			for _, q := range [4][6]float64{
+				{x + r, y - k*r, x + k*r, y - r, x, y - r},
+				{x - k*r, y - r, x - r, y - k*r, x - r, y},
+				{x - r, y + k*r, x - k*r, y + r, x, y + r},
+				{x + k*r, y + r, x + r, y + k*r, x + r, y},
+			} {
+				fmt.Fprintf(&b, " %s %s %s %s %s %s c", num(q[0]), flip(q[1]), num(q[2]), flip(q[3]), num(q[4]), flip(q[5]))
+			}
+			b.WriteString(" f\n")
+		case markText:
+			x := m.x1
+			switch m.align {
+			case alignCenter:
+				x -= textWidth(m.text, m.size, m.bold) / 2
+			case alignRight:
+				x -= textWidth(m.text, m.size, m.bold)
+			}
+			font := "F1"
+			if m.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&b, "BT /%s %s Tf %s %s Td %s Tj ET\n", font, num(m.size), num(x), flip(m.y1), pdfString(m.text))
+		}
+	}
+	return b.Bytes()
+}
+
+// pdfString writes text as a PDF string in the fonts' WinAnsi encoding,
+// which matches Latin-1 for the characters it has; others become "?".
+func pdfString(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~', r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
@@ -0,0 +1,370 @@
+// internal/export/render.go
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// Tabs are drawn for print by laying them out once as pages of simple
+// marks (lines, curves, blanks and text), in points from the top left
+// corner of the page, which the SVG and PDF writers then draw.
+
+// Page and tab dimensions, in points.
+const (
+	pageWidth   = 595 // A4
+	pageHeight  = 842
+	pageMargin  = 42
+	labelWidth  = 14 // string names left of the staff
+	columnWidth = 7  // one tab column
+	stringGap   = 8
+	fretSize    = 7.5
+	systemAbove = 18 // room for measure numbers and bends
+	systemBelow = 14
+)
+
+type markKind int
+
+const (
+	markLine  markKind = iota
+	markCurve          // quadratic, through control point C
+	markBlank          // white box, hiding the lines under text
+	markDot            // filled circle of radius width around 1
+	markText
+)
+
+type textAlign int
+
+const (
+	alignLeft textAlign = iota
+	alignCenter
+	alignRight
+)
+
+// mark is one thing drawn on a page. Lines and curves run from 1 to 2;
+// a blank covers the box with corners 1 and 2; dots and text are placed
+// at 1, text on its baseline.
+type mark struct {
+	kind   markKind
+	x1, y1 float64
+	x2, y2 float64
+	cx, cy float64
+	width  float64 // of lines and curves; radius of dots
+	text   string
+	size   float64
+	bold   bool
+	align  textAlign
+}
+
+type renderedPage struct {
+	marks []mark
+}
+
+// rendering is a tab laid out on pages of the same size.
+type rendering struct {
+	width, height float64
+	pages         []*renderedPage
+}
+
+// layout places the parts of a tab on pages, top to bottom.
+type layout struct {
+	rendering
+	y float64 // where the next part goes on the last page
+}
+
+// renderTab lays a tab out for print: a title block with the name, artist,
+// tempo, tuning and time signature, then systems of whole measures with
+// numbered measures and technique markings, then the notes. A height of 0
+// puts everything on one page as tall as it needs; otherwise the tab
+// breaks onto as many pages as it takes, numbered at the foot.
+func renderTab(tab *models.Tab, height float64) rendering {
+	l := &layout{rendering: rendering{width: pageWidth, height: height}}
+	l.newPage()
+
+	l.text(pageWidth/2, l.y+16, tab.Name, 16, true, alignCenter)
+	l.y += 24
+	if tab.Artist != "" {
+		l.text(pageWidth/2, l.y+10, tab.Artist, 10, false, alignCenter)
+		l.y += 16
+	}
+	info := fmt.Sprintf("Tempo %d bpm     Tuning %s     %s", tab.Tempo, tab.TuningString(), tab.TimeSignature)
+	l.text(pageMargin, l.y+10, info, 8.5, false, alignLeft)
+	l.y += 22
+
+	columns := int((pageWidth - 2*pageMargin - labelWidth) / columnWidth)
+	measures := tab.Measures()
+	for _, system := range groupSystems(tab, measures, columns) {
+		l.system(tab, measures, system, columns)
+	}
+
+	if notes := strings.TrimSpace(tab.Notes); notes != "" {
+		l.y += 6
+		for _, line := range strings.Split(notes, "\n") {
+			for _, wrapped := range wrapText(line, 9, pageWidth-2*pageMargin) {
+				l.room(12)
+				l.text(pageMargin, l.y+9, wrapped, 9, false, alignLeft)
+				l.y += 12
+			}
+		}
+	}
+
+	if l.height == 0 {
+		l.height = l.y + pageMargin
+	}
+	if len(l.pages) > 1 {
+		for i, page := range l.pages {
+			page.marks = append(page.marks, mark{kind: markText, x1: pageWidth / 2, y1: l.height - pageMargin/2,
+				text: fmt.Sprintf("%d / %d", i+1, len(l.pages)), size: 8, align: alignCenter})
+		}
+	}
+	return l.rendering
+}
+
+func (l *layout) newPage() {
+	l.pages = append(l.pages, &renderedPage{})
+	l.y = pageMargin
+}
+
+// room starts a new page unless height fits on this one.
+func (l *layout) room(height float64) {
+	if l.height > 0 && l.y+height > l.height-pageMargin {
+		l.newPage()
+	}
+}
+
+func (l *layout) add(m mark) {
+	page := l.pages[len(l.pages)-1]
+	page.marks = append(page.marks, m)
+}
+
+func (l *layout) line(x1, y1, x2, y2, width float64) {
+	l.add(mark{kind: markLine, x1: x1, y1: y1, x2: x2, y2: y2, width: width})
+}
+
+func (l *layout) curve(x1, y1, cx, cy, x2, y2 float64) {
+	l.add(mark{kind: markCurve, x1: x1, y1: y1, cx: cx, cy: cy, x2: x2, y2: y2, width: 0.5})
+}
+
+func (l *layout) text(x, y float64, text string, size float64, bold bool, align textAlign) {
+	l.add(mark{kind: markText, x1: x, y1: y, text: text, size: size, bold: bold, align: align})
+}
+
+// label writes text centered on a string line at x, blanking the line
+// behind it.
+func (l *layout) label(x, y float64, text string) {
+	half := textWidth(text, fretSize, false)/2 + 0.8
+	l.add(mark{kind: markBlank, x1: x - half, y1: y - fretSize/2, x2: x + half, y2: y + fretSize/2})
+	l.text(x, y+fretSize*0.35, text, fretSize, false, alignCenter)
+}
+
+// groupSystems splits the measures into systems of at most columns
+// columns, counting the bar line added after measures without one. A
+// measure wider than that gets a system of its own and is squeezed to fit.
+func groupSystems(tab *models.Tab, measures []models.Span, columns int) [][]int {
+	top := []rune(tab.Content[0])
+	var systems [][]int
+	used := 0
+	for i, m := range measures {
+		cols := m.Len()
+		if m.End > len(top) || top[m.End-1] != '|' {
+			cols++
+		}
+		if n := len(systems); n > 0 && used+cols <= columns {
+			systems[n-1] = append(systems[n-1], i)
+			used += cols
+			continue
+		}
+		systems = append(systems, []int{i})
+		used = cols
+	}
+	return systems
+}
+
+// system draws the measures of one system: the staff with the string
+// names, bar lines, measure numbers, and every string's notes.
+func (l *layout) system(tab *models.Tab, measures []models.Span, system []int, columns int) {
+	var lines [6][]rune
+	var starts []int // column of each measure in the system
+	top := []rune(tab.Content[0])
+	for _, index := range system {
+		m := measures[index]
+		starts = append(starts, len(lines[0]))
+		for s, line := range tab.Content {
+			lines[s] = append(lines[s], []rune(cells(line, m))...)
+			if m.End > len(top) || top[m.End-1] != '|' {
+				lines[s] = append(lines[s], '|')
+			}
+		}
+	}
+
+	width := float64(columnWidth)
+	if n := len(lines[0]); n > columns {
+		width = float64(columns) * columnWidth / float64(n)
+	}
+	l.room(systemAbove + 5*stringGap + systemBelow)
+	y0 := l.y + systemAbove
+	y5 := y0 + 5*stringGap
+	x0 := float64(pageMargin + labelWidth)
+	column := func(c float64) float64 { return x0 + (c+0.5)*width }
+
+	end := x0 + float64(len(lines[0]))*width
+	if n := len(lines[0]); n > 0 && lines[0][n-1] == '|' {
+		end = column(float64(n - 1))
+	}
+	for s, name := range tab.TuningLabels() {
+		y := y0 + float64(s)*stringGap
+		l.line(x0, y, end, y, 0.5)
+		l.text(pageMargin+labelWidth/2, y+2.8, strings.TrimSpace(name), 8, false, alignCenter)
+	}
+	l.line(x0, y0, x0, y5, 0.8)
+	for c, r := range lines[0] {
+		if r == '|' {
+			l.line(column(float64(c)), y0, column(float64(c)), y5, 0.6)
+		}
+	}
+	for i, index := range system {
+		l.text(x0+float64(starts[i])*width+1, y0-6, fmt.Sprint(index+1), 6.5, false, alignLeft)
+	}
+
+	for s, line := range lines {
+		l.stringLine(line, s, y0, column, width)
+	}
+	l.y = y5 + systemBelow
+}
+
+// stringLine draws the notes and techniques of one string of a system.
+// Hammer-ons and pull-offs get an arc, slides become lines and bends
+// arrows between the notes they join, and repeat signs dots; other
+// symbols are written as they are.
+func (l *layout) stringLine(line []rune, s int, top float64, column func(float64) float64, width float64) {
+	y := top + float64(s)*stringGap
+	notes := models.ParseNotes(string(line), s)
+	at := make(map[int]int, len(notes)) // index in notes by position
+	for i, n := range notes {
+		at[n.Position] = i
+	}
+	center := func(n models.Note) float64 { return column(float64(n.Position) + float64(n.Width-1)/2) }
+	// next returns the note after pos in the same measure, if any
+	next := func(pos int) (models.Note, bool) {
+		for p := pos + 1; p < len(line) && line[p] != '|'; p++ {
+			if i, ok := at[p]; ok {
+				return notes[i], true
+			}
+		}
+		return models.Note{}, false
+	}
+
+	var last *models.Note // the note a technique belongs to
+	for pos := 0; pos < len(line); pos++ {
+		if i, ok := at[pos]; ok {
+			n := notes[i]
+			l.label(center(n), y, fmt.Sprint(n.Fret))
+			last = &notes[i]
+			pos += n.Width - 1
+			continue
+		}
+
+		r := line[pos]
+		to, joined := next(pos)
+		switch {
+		case r == '-':
+			continue
+		case r == '|':
+			last = nil
+			continue
+		case (r == 'h' || r == 'p') && last != nil && joined:
+			x1, x2 := center(*last), center(to)
+			l.curve(x1, y-4, (x1+x2)/2, y-9, x2, y-4)
+			l.label(column(float64(pos)), y, string(r))
+		case r == '*' || r == ':':
+			l.add(mark{kind: markDot, x1: column(float64(pos)), y1: y, width: 1.3})
+		case (r == '/' || r == '\\') && last != nil && joined:
+			x1, x2 := center(*last)+width*0.6, center(to)-width*0.6
+			rise := 2.0
+			if r == '\\' {
+				rise = -rise
+			}
+			l.line(x1, y+rise, x2, y-rise, 0.5)
+		case r == 'b' && last != nil:
+			amount := 2 // a bare "b" is a whole step
+			if joined && to.Position == pos+1 {
+				amount = to.Fret - last.Fret
+				pos += to.Width // the target is the arrow's label, not a note
+			}
+			l.bend(center(*last)+width*0.8, y, top, amount)
+			last = nil
+		default:
+			l.label(column(float64(pos)), y, string(r))
+		}
+	}
+}
+
+// bend draws an arrow curving up from a string to above the staff,
+// labelled with how far the note is bent.
+func (l *layout) bend(x, y, top float64, semitones int) {
+	tipX, tipY := x+columnWidth, top-9
+	l.curve(x, y-1, tipX, y-1, tipX, tipY)
+	l.line(tipX, tipY, tipX-1.5, tipY+2.5, 0.5)
+	l.line(tipX, tipY, tipX+1.5, tipY+2.5, 0.5)
+
+	label := "full"
+	switch {
+	case semitones == 1:
+		label = "½"
+	case semitones%2 == 1:
+		label = fmt.Sprintf("%d½", semitones/2)
+	case semitones != 2:
+		label = fmt.Sprint(semitones / 2)
+	}
+	l.text(tipX, tipY-1.5, label, 6, false, alignCenter)
+}
+
+// wrapText breaks text into lines no wider than width at size.
+func wrapText(text string, size, width float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	line := words[0]
+	for _, w := range words[1:] {
+		if textWidth(line+" "+w, size, false) > width {
+			lines = append(lines, line)
+			line = w
+			continue
+		}
+		line += " " + w
+	}
+	return append(lines, line)
+}
+
+// helveticaWidths are the advance widths of Helvetica's printable ASCII
+// characters, from the space, in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth measures text set in Helvetica. Bold is taken as a little
+// wider throughout, and characters outside ASCII as the width of a digit.
+func textWidth(text string, size float64, bold bool) float64 {
+	total := 0
+	for _, r := range text {
+		if r >= ' ' && r <= '~' {
+			total += helveticaWidths[r-' ']
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if bold {
+		width *= 1.06
+	}
+	return width
+}
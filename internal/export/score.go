@@ -0,0 +1,196 @@
+// internal/export/score.go
+package export
+
+import (
+	"sort"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// scoreNote is a note of the tab with what notation formats need to know
+// about it.
+type scoreNote struct {
+	String   int // 0 is the highest string
+	Fret     int
+	Pitch    int // MIDI note
+	Dead     bool
+	Ghost    bool
+	Harmonic bool
+	Legato   rune // 'h', 'p', '/' or '\\' joining the note to the next on its string; 0 if none
+	Bend     int  // semitones; 0 if none
+	Vibrato  bool
+}
+
+// scoreEvent is the notes starting at one column of a measure. It lasts
+// until the next event or the end of the measure; without notes it is a
+// rest.
+type scoreEvent struct {
+	Column int // from the start of the measure
+	Length int // in columns
+	Notes  []scoreNote
+}
+
+// scoreMeasure is a measure of the tab read as a sequence of events.
+type scoreMeasure struct {
+	Length      int // in columns, without the bar line and repeat dots
+	Events      []scoreEvent
+	RepeatStart bool
+	RepeatEnd   bool
+}
+
+// scoreMeasures reads the tab's measures as rhythm: every column is a
+// sixteenth note, and a note lasts until the next note starts on any
+// string. Technique letters after a note ("5h7", "7b9", "5~") are read into
+// the note they follow, and repeat dots ('*' or ':' on the middle strings)
+// just inside a bar line into the measure.
+func scoreMeasures(tab *models.Tab) []scoreMeasure {
+	open := tab.OpenStrings()
+	top := []rune(tab.Content[0])
+
+	var lines [6][]rune
+	var frets [6]map[int]models.Note
+	for s, line := range tab.Content {
+		lines[s] = []rune(line)
+		frets[s] = make(map[int]models.Note)
+		for _, n := range models.ParseNotes(line, s) {
+			frets[s][n.Position] = n
+		}
+	}
+
+	var measures []scoreMeasure
+	for _, span := range tab.Measures() {
+		end := span.End
+		if end <= len(top) && top[end-1] == '|' {
+			end--
+		}
+		start := span.Start
+		repeatStart := start < end && repeatDots(lines, start)
+		if repeatStart {
+			start++
+		}
+		repeatEnd := start < end && repeatDots(lines, end-1)
+		if repeatEnd {
+			end--
+		}
+
+		byColumn := make(map[int][]scoreNote)
+		for s := range lines {
+			for _, n := range stringNotes(lines[s], frets[s], s, open[s], start, end) {
+				byColumn[n.column] = append(byColumn[n.column], n.scoreNote)
+			}
+		}
+
+		measure := scoreMeasure{Length: end - start, RepeatStart: repeatStart, RepeatEnd: repeatEnd}
+		columns := make([]int, 0, len(byColumn))
+		for c := range byColumn {
+			columns = append(columns, c)
+		}
+		sort.Ints(columns)
+		if len(columns) == 0 || columns[0] > 0 {
+			columns = append([]int{0}, columns...)
+		}
+		for i, c := range columns {
+			next := measure.Length
+			if i+1 < len(columns) {
+				next = columns[i+1]
+			}
+			if next <= c {
+				continue
+			}
+			notes := byColumn[c]
+			sort.Slice(notes, func(a, b int) bool { return notes[a].String < notes[b].String })
+			measure.Events = append(measure.Events, scoreEvent{Column: c, Length: next - c, Notes: notes})
+		}
+		measures = append(measures, measure)
+	}
+	return measures
+}
+
+// repeatDots reports whether column holds repeat dots, as in
+//
+//	G|*-----*|
+//	D|*-----*|
+//
+// with nothing but rests on the other strings.
+func repeatDots(lines [6][]rune, column int) bool {
+	dots := false
+	for s, line := range lines {
+		if column >= len(line) {
+			continue
+		}
+		switch r := line[column]; {
+		case (r == '*' || r == ':') && s > 0 && s < len(lines)-1:
+			dots = true
+		case r != '-' && r != '|':
+			return false
+		}
+	}
+	return dots
+}
+
+type placedNote struct {
+	scoreNote
+	column int
+}
+
+// stringNotes reads the notes of one string between columns start and end.
+func stringNotes(line []rune, frets map[int]models.Note, str, open, start, end int) []placedNote {
+	var notes []placedNote
+	last := -1 // the note a technique letter belongs to
+	for pos := start; pos < end && pos < len(line); pos++ {
+		r := line[pos]
+		if n, ok := frets[pos]; ok {
+			note := placedNote{scoreNote{String: str, Fret: n.Fret, Pitch: open + n.Fret}, pos - start}
+			if pos > 0 {
+				note.Ghost = line[pos-1] == '('
+				note.Harmonic = line[pos-1] == '<'
+			}
+			notes = append(notes, note)
+			last = len(notes) - 1
+			pos += n.Width - 1
+			continue
+		}
+
+		switch r {
+		case 'x', 'X':
+			notes = append(notes, placedNote{scoreNote{String: str, Pitch: open, Dead: true}, pos - start})
+			last = len(notes) - 1
+		case 'h', 'p', '/', '\\':
+			if last >= 0 {
+				notes[last].Legato = r
+			}
+		case 'b':
+			if last < 0 {
+				continue
+			}
+			// "7b9" bends fret 7 up to the pitch of fret 9; a bare "b" is
+			// taken as a whole step
+			notes[last].Bend = 2
+			if n, ok := frets[pos+1]; ok && pos+1 < end {
+				notes[last].Bend = n.Fret - notes[last].Fret
+				pos += n.Width
+			}
+		case '~':
+			if last >= 0 {
+				notes[last].Vibrato = true
+			}
+		case ')', '>', 'r':
+		default:
+			last = -1
+		}
+	}
+	return notes
+}
+
+// noteLengths splits a length in sixteenths into lengths that can be
+// written as single, possibly dotted, notes, longest first.
+func noteLengths(length int) []int {
+	var parts []int
+	for _, part := range []int{16, 12, 8, 6, 4, 3, 2, 1} {
+		for length >= part {
+			parts = append(parts, part)
+			length -= part
+		}
+	}
+	return parts
+}
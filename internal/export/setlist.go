@@ -0,0 +1,63 @@
+// internal/export/setlist.go
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// WriteSetlist writes a setlist as one plain-text document: the running
+// order, then every song's tab with its setlist notes. tabs maps tab IDs to
+// tabs; entries whose tab is missing are listed but have no music. Tab
+// lines are broken at bar lines to fit width columns.
+func WriteSetlist(w io.Writer, setlist *models.Setlist, tabs map[int]*models.Tab, width int) error {
+	var b strings.Builder
+
+	b.WriteString(setlist.Name + "\n")
+	b.WriteString(strings.Repeat("=", len([]rune(setlist.Name))) + "\n\n")
+
+	for i, entry := range setlist.Entries {
+		tab, ok := tabs[entry.TabID]
+		if !ok {
+			fmt.Fprintf(&b, "%2d. (missing tab #%d)\n", i+1, entry.TabID)
+			continue
+		}
+		fmt.Fprintf(&b, "%2d. %s\n", i+1, songTitle(tab))
+	}
+
+	for i, entry := range setlist.Entries {
+		tab, ok := tabs[entry.TabID]
+		if !ok {
+			continue
+		}
+
+		title := fmt.Sprintf("%d. %s", i+1, songTitle(tab))
+		b.WriteString("\n\n" + title + "\n")
+		b.WriteString(strings.Repeat("-", len([]rune(title))) + "\n")
+
+		var details []string
+		if entry.Capo > 0 {
+			details = append(details, fmt.Sprintf("Capo %d", entry.Capo))
+		}
+		if entry.Transpose != 0 {
+			details = append(details, fmt.Sprintf("Transpose %+d", entry.Transpose))
+		}
+		details = append(details,
+			fmt.Sprintf("Tempo %d bpm", entry.TempoFor(tab)),
+			"Tuning "+tab.TuningString(),
+			tab.TimeSignature)
+		b.WriteString(strings.Join(details, " | ") + "\n")
+		if entry.Notes != "" {
+			b.WriteString("Notes: " + entry.Notes + "\n")
+		}
+		b.WriteString("\n")
+
+		writeSystems(&b, tab, width, false)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,64 @@
+// internal/export/svg.go
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// WriteSVG draws a tab as a single SVG image as tall as the tab needs,
+// laid out as by renderTab.
+func WriteSVG(w io.Writer, tab *models.Tab) error {
+	r := renderTab(tab, 0)
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%spt\" height=\"%spt\" viewBox=\"0 0 %s %s\">\n",
+		num(r.width), num(r.height), num(r.width), num(r.height))
+	fmt.Fprintf(&b, "<rect width=\"%s\" height=\"%s\" fill=\"white\"/>\n", num(r.width), num(r.height))
+	b.WriteString("<g font-family=\"Helvetica, Arial, sans-serif\" stroke-linecap=\"round\">\n")
+
+	for _, m := range r.pages[0].marks {
+		switch m.kind {
+		case markLine:
+			fmt.Fprintf(&b, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\" stroke=\"black\" stroke-width=\"%s\"/>\n",
+				num(m.x1), num(m.y1), num(m.x2), num(m.y2), num(m.width))
+		case markCurve:
+			fmt.Fprintf(&b, "<path d=\"M%s %s Q%s %s %s %s\" fill=\"none\" stroke=\"black\" stroke-width=\"%s\"/>\n",
+				num(m.x1), num(m.y1), num(m.cx), num(m.cy), num(m.x2), num(m.y2), num(m.width))
+		case markBlank:
+			fmt.Fprintf(&b, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"white\"/>\n",
+				num(m.x1), num(m.y1), num(m.x2-m.x1), num(m.y2-m.y1))
+		case markDot:
+			fmt.Fprintf(&b, "<circle cx=\"%s\" cy=\"%s\" r=\"%s\"/>\n", num(m.x1), num(m.y1), num(m.width))
+		case markText:
+			fmt.Fprintf(&b, "<text x=\"%s\" y=\"%s\" font-size=\"%s\"", num(m.x1), num(m.y1), num(m.size))
+			if m.bold {
+				b.WriteString(" font-weight=\"bold\"")
+			}
+			switch m.align {
+			case alignCenter:
+				b.WriteString(" text-anchor=\"middle\"")
+			case alignRight:
+				b.WriteString(" text-anchor=\"end\"")
+			}
+			b.WriteString(">")
+			xml.EscapeText(&b, []byte(m.text))
+			b.WriteString("</text>\n")
+		}
+	}
+
+	b.WriteString("</g>\n</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// num writes a coordinate to two decimal places without trailing zeros.
+func num(f float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", f), "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,309 @@
+// internal/importer/ascii.go
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// Problem is an input line the importer could not use, or for binary
+// formats something in the file it could not keep.
+type Problem struct {
+	Line   int // 1-based; 0 if the problem is not about a line
+	Text   string
+	Reason string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Reason
+	}
+	return fmt.Sprintf("line %d: %s", p.Line, p.Reason)
+}
+
+// tabChars are the characters kept in tab lines besides fret digits:
+// rests, bar lines and technique letters (hammer-on, pull-off, bend,
+// release, slides, vibrato, mutes, taps, harmonics, ghost notes).
+const tabChars = "-|hpbrs/\\~xtv()<>^.*="
+
+// metaLine matches header lines like "Artist: Foo" above the first system.
+var metaLine = regexp.MustCompile(`(?i)^\s*` +
+	`(title|song|artist|band|tempo|bpm|tuning|time|time signature)` +
+	`\s*:\s*(.+?)\s*$`)
+
+// tabLine is one parsed string line of a system.
+type tabLine struct {
+	line  int
+	text  string
+	label string
+	body  string
+}
+
+// ParseASCII reads a plain-text tab as pasted from tab sites: systems of six
+// labelled string lines ("e|--0--3--|"), with chord names, lyrics and other
+// text in between. The systems are stitched into one tab; the text becomes
+// the tab's notes. The tuning comes from the string labels of the first
+// system, and the name from a "Title:" line if there is one. Lines that
+// look like tab but cannot be used are reported as problems; an error is
+// returned only if no system is found at all.
+func ParseASCII(r io.Reader) (*models.Tab, []Problem, error) {
+	tab := models.NewEmptyTab("")
+	tab.Content = [6]string{}
+
+	var (
+		problems []Problem
+		system   []tabLine
+		notes    []string
+		systems  int
+		labels   []string
+	)
+
+	flush := func() {
+		if len(system) == 0 {
+			return
+		}
+		defer func() { system = nil }()
+
+		if len(system) != len(tab.Content) {
+			problems = append(problems, Problem{system[0].line, system[0].text,
+				fmt.Sprintf("system of %d strings skipped; only 6-string tabs can be imported", len(system))})
+			return
+		}
+
+		if systems == 0 {
+			labels = make([]string, len(system))
+			for i, l := range system {
+				labels[i] = l.label
+			}
+		} else {
+			for i, l := range system {
+				if l.label != "" && labels[i] != "" && !strings.EqualFold(l.label, labels[i]) {
+					problems = append(problems, Problem{l.line, l.text,
+						fmt.Sprintf("string label %s does not match %s in the first system", l.label, labels[i])})
+				}
+			}
+		}
+		appendSystem(tab, system)
+		systems++
+	}
+
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(strings.ReplaceAll(scanner.Text(), "\t", "    "), " \r")
+
+		if l, ok := parseTabLine(text); ok {
+			l.line, l.text = number, text
+			body, bad := cleanBody(l.body)
+			if bad != "" {
+				problems = append(problems, Problem{number, text,
+					fmt.Sprintf("unknown tab characters %q replaced with rests", bad)})
+			}
+			l.body = body
+			system = append(system, l)
+			continue
+		}
+		flush()
+
+		if looksLikeTab(text) {
+			problems = append(problems, Problem{number, text, "looks like tab but has no string label"})
+			continue
+		}
+		if systems == 0 && applyMeta(tab, text) {
+			continue
+		}
+		if strings.TrimSpace(text) == "" && (len(notes) == 0 || notes[len(notes)-1] == "") {
+			continue
+		}
+		notes = append(notes, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, problems, err
+	}
+	flush()
+
+	if systems == 0 {
+		return nil, problems, fmt.Errorf("no tab systems found")
+	}
+
+	if tuning, ok := tuningFromLabels(labels); ok {
+		tab.Tuning = tuning
+	}
+	tab.Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+	return tab, problems, nil
+}
+
+// parseTabLine splits a line like "e|--0--|" or "D --2--" into its string
+// label and its body, without the opening bar line. Unlabelled lines that
+// start with a bar line count as tab too.
+func parseTabLine(text string) (tabLine, bool) {
+	s := strings.TrimLeft(text, " ")
+	if s == "" {
+		return tabLine{}, false
+	}
+
+	var label string
+	if strings.ContainsRune("ABCDEFGabcdefg", rune(s[0])) {
+		label, s = s[:1], s[1:]
+		if len(s) > 1 && (s[0] == '#' || s[0] == 'b') && strings.ContainsRune("|:- ", rune(s[1])) {
+			label, s = label+s[:1], s[1:]
+		}
+		s = strings.TrimLeft(s, " ")
+	}
+
+	switch {
+	case strings.HasPrefix(s, "|"), strings.HasPrefix(s, ":"):
+		s = s[1:]
+	case strings.HasPrefix(s, "-") && label != "":
+	default:
+		return tabLine{}, false
+	}
+
+	s = trimAnnotation(s)
+	if strings.Count(s, "-") < 2 {
+		return tabLine{}, false
+	}
+	return tabLine{label: label, body: s}, true
+}
+
+// trimAnnotation cuts text after the tab itself, like "x4" after the last
+// bar. Gaps between runs of tab are kept as rests.
+func trimAnnotation(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] != ' ' {
+			continue
+		}
+		j := i
+		for j < len(s) && s[j] == ' ' {
+			j++
+		}
+		next, _, _ := strings.Cut(s[j:], " ")
+		if !isTabRun(next) {
+			s = s[:i]
+			break
+		}
+		i = j - 1
+	}
+	return strings.ReplaceAll(s, " ", "-")
+}
+
+func isTabRun(s string) bool {
+	if !strings.ContainsAny(s, "-|") {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) && !strings.ContainsRune(tabChars, unicode.ToLower(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanBody replaces characters that are neither frets nor known tab
+// symbols with rests, returning the ones it replaced.
+func cleanBody(body string) (string, string) {
+	var b, bad strings.Builder
+	for _, r := range body {
+		if unicode.IsDigit(r) || strings.ContainsRune(tabChars, unicode.ToLower(r)) {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteByte('-')
+		bad.WriteRune(r)
+	}
+	return b.String(), bad.String()
+}
+
+// looksLikeTab reports whether a line that did not parse as tab still looks
+// like one, so it is worth reporting rather than keeping as text.
+func looksLikeTab(text string) bool {
+	return strings.Contains(text, "---") && strings.Contains(text, "|")
+}
+
+// appendSystem adds a system to the end of the tab. Ragged lines are padded
+// with rests, and a bar line separates systems that do not end in one.
+func appendSystem(tab *models.Tab, system []tabLine) {
+	width := 0
+	for _, l := range system {
+		width = max(width, len([]rune(l.body)))
+	}
+
+	bar := tab.Content[0] != "" && !strings.HasSuffix(tab.Content[0], "|") && !strings.HasPrefix(system[0].body, "|")
+	for i, l := range system {
+		if bar {
+			tab.Content[i] += "|"
+		}
+		tab.Content[i] += l.body + strings.Repeat("-", width-len([]rune(l.body)))
+	}
+}
+
+// applyMeta reads header lines like "Artist: Foo" or "Tempo: 90" into the
+// tab. It reports whether the line was one.
+func applyMeta(tab *models.Tab, text string) bool {
+	m := metaLine.FindStringSubmatch(text)
+	if m == nil {
+		return false
+	}
+
+	value := m[2]
+	switch strings.ToLower(m[1]) {
+	case "title", "song":
+		tab.Name = value
+	case "artist", "band":
+		tab.Artist = value
+	case "tempo", "bpm":
+		// \s in metaLine leaves Unicode spaces such as NBSP in value
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return false
+		}
+		tempo, err := strconv.Atoi(fields[0])
+		if err != nil || tempo < 20 || tempo > 300 {
+			return false
+		}
+		tab.Tempo = tempo
+	case "time", "time signature":
+		if beats, unit := models.ParseTimeSignature(value); fmt.Sprintf("%d/%d", beats, unit) == value {
+			tab.TimeSignature = value
+		}
+	case "tuning":
+		// "Tuning: D A D G B E", lowest string first; names like "Drop D"
+		// are left to the string labels
+		tuning, ok := models.ParseTuning(value)
+		if !ok {
+			return false
+		}
+		tab.Tuning = tuning
+	}
+	return true
+}
+
+// tuningFromLabels turns the labels of the first system, top string first,
+// into a tuning. Tab sites often write both E strings in capitals; the
+// high one is lowercased the way tuitar writes it.
+func tuningFromLabels(labels []string) ([6]string, bool) {
+	var tuning [6]string
+	if len(labels) != len(tuning) {
+		return tuning, false
+	}
+	for i, label := range labels {
+		if label == "" {
+			return tuning, false
+		}
+		tuning[i] = label
+	}
+
+	top, bottom := tuning[0], tuning[len(tuning)-1]
+	if strings.EqualFold(top, bottom) && top == strings.ToUpper(top) {
+		tuning[0] = strings.ToLower(top[:1]) + top[1:]
+	}
+	return tuning, true
+}
@@ -0,0 +1,357 @@
+// internal/importer/chordpro.go
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// directive matches a ChordPro directive line: "{title: Song}", "{soc}" or
+// "{define Am base-fret 1 frets x 0 2 2 1 0}". A selector after the name
+// ("{title-guitar: ...}") is dropped.
+var directive = regexp.MustCompile(`^\{\s*([A-Za-z_]+)(?:-[A-Za-z0-9_]+)?\s*(?:[:\s]\s*(.*?))?\s*\}$`)
+
+// chordProSections are the short forms of the section directives.
+var chordProSections = map[string]string{
+	"soc": "start_of_chorus", "eoc": "end_of_chorus",
+	"sov": "start_of_verse", "eov": "end_of_verse",
+	"sob": "start_of_bridge", "eob": "end_of_bridge",
+	"sot": "start_of_tab", "eot": "end_of_tab",
+	"sog": "start_of_grid", "eog": "end_of_grid",
+}
+
+// chordProLabels are the directives kept in the notes as "Label: value"
+// lines, having no field of their own in a tab.
+var chordProLabels = map[string]string{
+	"key": "Key", "composer": "Composer", "lyricist": "Lyricist", "album": "Album",
+	"year": "Year", "copyright": "Copyright", "duration": "Duration",
+}
+
+// chordProIgnored are directives about how a sheet is typeset, which mean
+// nothing in a tab.
+var chordProIgnored = map[string]bool{
+	"new_page": true, "np": true, "new_physical_page": true, "npp": true,
+	"column_break": true, "colb": true, "columns": true, "col": true,
+	"pagetype": true, "titles": true, "grid": true, "g": true, "no_grid": true, "ng": true,
+	"image": true, "diagrams": true, "transpose": true,
+}
+
+// chordSheet is one song of a ChordPro file as it is read.
+type chordSheet struct {
+	tab      *models.Tab
+	subtitle string
+	meta     []string // "Capo N" and "Label: value" lines heading the notes
+	lines    []string // the sheet, chords written over their lyrics
+	tuned    bool     // the tuning was given
+	ended    bool     // a section just ended; the next line starts apart
+
+	defined map[string][6]int
+	defines []string // chord names in the order they were defined
+	chords  []string // chord names in the order they are first played
+	played  map[string]bool
+
+	inTab    bool
+	tabLines []string
+	tabAt    int // where in lines the first tab section was
+}
+
+func newChordSheet() *chordSheet {
+	tab := models.NewEmptyTab("")
+	return &chordSheet{tab: tab, defined: make(map[string][6]int), played: make(map[string]bool), tabAt: -1}
+}
+
+// ParseChordPro reads a ChordPro song sheet. Each song becomes a tab whose
+// notes hold the sheet, with the chords written above the lyrics they are
+// sung on and sections headed "[Chorus]" and the like; the tab itself is a
+// chord chart, a measure for each chord in the order the song first plays
+// it, fingered by its {define} or else a common shape, and named by a
+// "Bar N: chord" line. Tab sections are read as tab and follow the chart.
+// Comments stay on the sheet as "Comment: text" lines. The title, artist
+// (or else the subtitle), tempo, time signature, capo and a {meta: tuning}
+// carry over.
+func ParseChordPro(r io.Reader, name string) ([]*models.Tab, []Problem, error) {
+	var (
+		tabs     []*models.Tab
+		problems []Problem
+	)
+	sheet := newChordSheet()
+	finish := func() {
+		tab, sheetProblems := sheet.finish(name)
+		problems = append(problems, sheetProblems...)
+		if tab != nil {
+			tabs = append(tabs, tab)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		m := directive.FindStringSubmatch(strings.TrimSpace(line))
+		switch {
+		case m != nil && (strings.EqualFold(m[1], "new_song") || strings.EqualFold(m[1], "ns")):
+			finish()
+			sheet = newChordSheet()
+		case m != nil:
+			if reason := sheet.directive(strings.ToLower(m[1]), m[2]); reason != "" {
+				problems = append(problems, Problem{Line: lineNum, Text: line, Reason: reason})
+			}
+		case sheet.inTab:
+			sheet.tabLines = append(sheet.tabLines, line)
+		case strings.HasPrefix(line, "#"):
+			// a comment in the file, not on the sheet
+		default:
+			sheet.lyrics(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	finish()
+
+	if len(tabs) == 0 {
+		return nil, problems, fmt.Errorf("no songs found")
+	}
+	return tabs, problems, nil
+}
+
+// directive applies a directive, returning why it was left out if it was.
+func (s *chordSheet) directive(name, value string) string {
+	if long, ok := chordProSections[name]; ok {
+		name = long
+	}
+
+	switch name {
+	case "title", "t":
+		s.tab.Name = value
+	case "subtitle", "st":
+		if s.subtitle != "" {
+			value = s.subtitle + ", " + value
+		}
+		s.subtitle = value
+	case "artist":
+		if s.tab.Artist != "" {
+			value = s.tab.Artist + ", " + value
+		}
+		s.tab.Artist = value
+	case "tempo":
+		if !applyMeta(s.tab, "Tempo: "+value) {
+			return fmt.Sprintf("tempo %q left out", value)
+		}
+	case "time":
+		if !applyMeta(s.tab, "Time: "+value) {
+			return fmt.Sprintf("time signature %q left out; tuitar keeps %s", value, s.tab.TimeSignature)
+		}
+	case "tuning":
+		if !applyMeta(s.tab, "Tuning: "+value) {
+			return fmt.Sprintf("tuning %q left out; it needs a name for each of the six strings", value)
+		}
+		s.tuned = true
+	case "capo":
+		capo, err := strconv.Atoi(value)
+		if err != nil || capo < 0 || capo > models.MaxFret {
+			return fmt.Sprintf("capo %q left out", value)
+		}
+		if capo > 0 {
+			s.meta = append(s.meta, fmt.Sprintf("Capo %d", capo))
+		}
+	case "meta":
+		key, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+		return s.directive(strings.ToLower(key), strings.TrimSpace(rest))
+	case "define", "chord":
+		chord, frets, ok := parseDefine(value)
+		if !ok {
+			return fmt.Sprintf("{%s} of %q could not be read and was left out", name, chord)
+		}
+		if _, seen := s.defined[chord]; !seen {
+			s.defines = append(s.defines, chord)
+		}
+		s.defined[chord] = frets
+	case "comment", "c", "comment_italic", "ci", "comment_box", "cb", "highlight":
+		s.add("Comment: " + value)
+	case "chorus":
+		if value == "" {
+			value = "Chorus"
+		}
+		s.add("[" + value + "]")
+	case "start_of_tab":
+		s.inTab = true
+		if s.tabAt < 0 {
+			s.tabAt = len(s.lines)
+		}
+	case "end_of_tab":
+		s.inTab = false
+		s.ended = true
+	default:
+		switch {
+		case chordProLabels[name] != "":
+			s.meta = append(s.meta, chordProLabels[name]+": "+value)
+		case strings.HasPrefix(name, "start_of_"):
+			if value == "" {
+				kind := strings.TrimPrefix(name, "start_of_")
+				value = strings.ToUpper(kind[:1]) + kind[1:]
+			}
+			s.add("[" + value + "]")
+		case strings.HasPrefix(name, "end_of_"):
+			s.ended = true
+		case chordProIgnored[name],
+			strings.HasSuffix(name, "font"), strings.HasSuffix(name, "size"), strings.HasSuffix(name, "colour"):
+		default:
+			return fmt.Sprintf("{%s} is not supported and was left out", name)
+		}
+	}
+	return ""
+}
+
+// add writes a line of the sheet, after a blank line if a section has
+// just ended, since sheets run sections to the next blank line.
+func (s *chordSheet) add(line string) {
+	if s.ended && line != "" && len(s.lines) > 0 && s.lines[len(s.lines)-1] != "" {
+		s.lines = append(s.lines, "")
+	}
+	s.ended = false
+	s.lines = append(s.lines, line)
+}
+
+// lyrics writes a line of the sheet, moving its inline chords ("[G]Here
+// comes the [C]sun") onto a line of their own above the lyrics. A chord
+// that would run into the one before it moves along to leave a space.
+func (s *chordSheet) lyrics(line string) {
+	if !strings.Contains(line, "[") {
+		s.add(line)
+		return
+	}
+
+	var chords, words []rune
+	rest := line
+	for {
+		open := strings.Index(rest, "[")
+		end := strings.Index(rest[max(open, 0):], "]")
+		if open < 0 || end < 0 {
+			words = append(words, []rune(rest)...)
+			break
+		}
+		words = append(words, []rune(rest[:open])...)
+		chord := strings.TrimSpace(rest[open+1 : open+end])
+		rest = rest[open+end+1:]
+
+		if strings.HasPrefix(chord, "*") {
+			chord = chord[1:] // an annotation, not a chord
+		} else if chordName.MatchString(chord) && !s.played[chord] {
+			s.played[chord] = true
+			s.chords = append(s.chords, chord)
+		}
+		if chord == "" {
+			continue
+		}
+		column := len(words)
+		if len(chords) > 0 {
+			column = max(column, len(chords)+1)
+		}
+		for len(chords) < column {
+			chords = append(chords, ' ')
+		}
+		chords = append(chords, []rune(chord)...)
+	}
+
+	s.add(string(chords))
+	if lyric := strings.TrimRight(string(words), " "); lyric != "" {
+		s.lines = append(s.lines, lyric)
+	}
+}
+
+// finish makes the tab of a song, or nil if the song was empty.
+func (s *chordSheet) finish(name string) (*models.Tab, []Problem) {
+	tab := s.tab
+	var problems []Problem
+	if tab.Name == "" && len(s.lines) == 0 && len(s.chords) == 0 && len(s.defines) == 0 && len(s.tabLines) == 0 {
+		return nil, nil
+	}
+	if tab.Name == "" {
+		tab.Name = name
+	}
+	switch {
+	case tab.Artist == "":
+		tab.Artist = s.subtitle
+	case s.subtitle != "":
+		s.meta = append([]string{"Subtitle: " + s.subtitle}, s.meta...)
+	}
+
+	// The chart has the chords played, then any defined and not played
+	chords := s.chords
+	for _, chord := range s.defines {
+		if !s.played[chord] {
+			chords = append(chords, chord)
+		}
+	}
+	standard := tab.OpenStrings() == models.StandardPitches
+
+	var (
+		events [][]tabEvent
+		widths []int
+		bars   []string
+	)
+	for _, chord := range chords {
+		frets, ok := s.defined[chord]
+		if !ok && standard {
+			frets, ok = commonShape(chord)
+		}
+		if !ok {
+			problems = append(problems, Problem{Reason: fmt.Sprintf("%s: no shape known for %s; add a {define} to chart it", tab.Name, chord)})
+			continue
+		}
+		event := tabEvent{}
+		for str, fret := range frets {
+			if fret >= 0 {
+				event.cells[str] = &tabCell{text: strconv.Itoa(fret), fret: fret}
+			}
+		}
+		events = append(events, []tabEvent{event})
+		widths = append(widths, tab.ColumnsPerMeasure())
+		bars = append(bars, fmt.Sprintf("Bar %d: %s", len(events), chord))
+	}
+	if len(events) > 0 {
+		tab.Content = writeMeasures(events, widths)
+	}
+
+	lines := s.lines
+	if len(s.tabLines) > 0 {
+		riff, _, err := ParseASCII(strings.NewReader(strings.Join(s.tabLines, "\n")))
+		switch {
+		case err != nil:
+			// Not tab after all; keep it as text where it was
+			lines = append(append(append([]string{}, lines[:s.tabAt]...), s.tabLines...), lines[s.tabAt:]...)
+		case len(events) > 0:
+			for str := range tab.Content {
+				tab.Content[str] += riff.Content[str]
+			}
+		default:
+			tab.Content = riff.Content
+		}
+		if err == nil && !s.tuned && len(events) == 0 {
+			tab.Tuning = riff.Tuning
+		}
+	}
+
+	notes := append(bars, s.meta...)
+	sheet := strings.Trim(strings.Join(lines, "\n"), "\n")
+	if sheet != "" {
+		if len(notes) > 0 {
+			notes = append(notes, "")
+		}
+		notes = append(notes, sheet)
+	}
+	tab.Notes = strings.Join(notes, "\n")
+	return tab, problems
+}
@@ -0,0 +1,157 @@
+// internal/importer/chords.go
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// chordName matches a chord symbol such as "G", "F#m7", "Bbsus4" or
+// "D/F#": a root, a quality and an optional bass note.
+var chordName = regexp.MustCompile(`^([A-G][#b]?)((?:maj|min|dim|aug|sus|add|m|M|[0-9]|[#b+()-])*)(?:/([A-G][#b]?))?$`)
+
+// chordQualities gives the common spellings of each quality a shape is
+// known for.
+var chordQualities = map[string]string{
+	"": "", "maj": "", "M": "",
+	"m": "m", "min": "m", "-": "m",
+	"7":  "7",
+	"m7": "m7", "min7": "m7", "-7": "m7",
+	"maj7": "maj7", "M7": "maj7",
+	"sus4": "sus4", "sus": "sus4",
+	"sus2":  "sus2",
+	"7sus4": "7sus4", "7sus": "7sus4",
+	"6":  "6",
+	"m6": "m6",
+	"5":  "5",
+}
+
+// openChords are the first-position shapes guitarists reach for before a
+// barre chord, written low E first as in chord charts.
+var openChords = map[string]string{
+	"C": "x32010", "C7": "x32310", "Cmaj7": "x32000", "Cadd9": "x32030",
+	"D": "xx0232", "Dm": "xx0231", "D7": "xx0212", "Dm7": "xx0211", "Dmaj7": "xx0222",
+	"Dsus2": "xx0230", "Dsus4": "xx0233",
+	"G": "320003", "G7": "320001", "Gmaj7": "320002",
+	"Fmaj7": "xx3210", "B7": "x21202",
+}
+
+// chordForms are the movable shapes of each quality with the root on the
+// low E string and on the A string, at fret 0.
+var chordForms = map[string][2]string{
+	"":      {"022100", "x02220"},
+	"m":     {"022000", "x02210"},
+	"7":     {"020100", "x02020"},
+	"m7":    {"020000", "x02010"},
+	"maj7":  {"021100", "x02120"},
+	"sus4":  {"022200", "x02230"},
+	"sus2":  {"", "x02200"},
+	"7sus4": {"020200", "x02030"},
+	"6":     {"022120", "x02222"},
+	"m6":    {"022020", "x02212"},
+	"5":     {"022xxx", "x022xx"},
+}
+
+// commonShape returns the frets of a common shape for a chord in standard
+// tuning, indexed like Tab.Content with -1 for strings not played: an open
+// chord if there is one, else whichever of the E and A barre shapes sits
+// lower on the neck. The bass note of a slash chord is not added.
+func commonShape(name string) ([6]int, bool) {
+	m := chordName.FindStringSubmatch(name)
+	if m == nil {
+		return [6]int{}, false
+	}
+	quality, ok := chordQualities[m[2]]
+	if !ok {
+		return [6]int{}, false
+	}
+	if shape, ok := openChords[m[1]+quality]; ok {
+		return parseShape(shape, 0)
+	}
+
+	root, _ := models.ParsePitchClass(m[1])
+	forms := chordForms[quality]
+	onE, onA := (root+8)%12, (root+3)%12 // the root's fret on the E and A strings
+	if forms[0] != "" && onE <= onA {
+		return parseShape(forms[0], onE)
+	}
+	return parseShape(forms[1], onA)
+}
+
+// parseShape reads a shape written low E first, one character a string,
+// moved up the neck by offset frets.
+func parseShape(shape string, offset int) ([6]int, bool) {
+	var frets [6]int
+	if len(shape) != len(frets) {
+		return frets, false
+	}
+	for i, r := range shape {
+		str := len(frets) - 1 - i
+		if r == 'x' {
+			frets[str] = -1
+			continue
+		}
+		frets[str] = int(r-'0') + offset
+	}
+	return frets, true
+}
+
+// parseDefine reads the shape of a ChordPro {define}: "Am base-fret 1
+// frets x 0 2 2 1 0", or the older "Am 1 x 0 2 2 1 0" with the base fret
+// first. Frets count from the base fret, low E first, with x or N for a
+// string not played. Definitions without frets, which only rename a known
+// chord, report false.
+func parseDefine(value string) (name string, frets [6]int, ok bool) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return "", frets, false
+	}
+	name, fields = fields[0], fields[1:]
+
+	base := 1
+	var written []string
+	if len(fields) == len(frets)+1 {
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			base, written = n, fields[1:]
+		}
+	}
+	for i := 0; i < len(fields); i++ {
+		switch strings.ToLower(fields[i]) {
+		case "base-fret":
+			if i+1 < len(fields) {
+				base, _ = strconv.Atoi(fields[i+1])
+				i++
+			}
+		case "frets":
+			end := min(i+1+len(frets), len(fields))
+			written = fields[i+1 : end]
+			i = end - 1
+		}
+	}
+	if len(written) != len(frets) || base < 1 {
+		return name, frets, false
+	}
+
+	for i, f := range written {
+		str := len(frets) - 1 - i
+		if f == "x" || f == "X" || f == "N" || f == "-1" {
+			frets[str] = -1
+			continue
+		}
+		fret, err := strconv.Atoi(f)
+		if err != nil || fret < 0 {
+			return name, frets, false
+		}
+		if fret > 0 {
+			fret += base - 1
+		}
+		if fret > models.MaxFret {
+			return name, frets, false
+		}
+		frets[str] = fret
+	}
+	return name, frets, true
+}
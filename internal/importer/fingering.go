@@ -0,0 +1,248 @@
+// internal/importer/fingering.go
+package importer
+
+import (
+	"sort"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// Notes read with only a pitch (MusicXML without a tab staff, MIDI) are
+// given a string and fret here. The fretting hand is modelled by the fret
+// its first finger is at; it covers that fret and the three above without
+// moving, and reaches one further at a stretch. Every chord in a passage
+// is fingered at once so that the hand moves as little as it can overall,
+// rather than as little as it can for the next chord.
+
+// fretting is where a pitch is played: a string (0 is the highest) and a
+// fret. String -1 means no string can play it.
+type fretting struct {
+	string int
+	fret   int
+}
+
+// maxStretch is the widest span of frets a chord can be fingered across.
+// It is a hard limit: notes a shape within it cannot reach are left out.
+const maxStretch = 4
+
+// Costs of a fingering. Leaving a note out is worse than moving the hand
+// anywhere; fretting a note over letting an open string ring, and high
+// positions over low ones, only break ties.
+const (
+	costUnplaced = 1000 // per pitch left out
+	costMove     = 10   // per fret the hand moves
+	costFretted  = 2    // per fretted note
+	costPosition = 1    // per fret up the neck
+)
+
+// maxShapes caps the fingerings of a chord considered against each other
+// across the passage.
+const maxShapes = 16
+
+// chordShape is one way of playing a chord.
+type chordShape struct {
+	frets     []fretting
+	cost      int
+	low, high int // fretted span; low is 0 if every note is open or left out
+}
+
+// shapes lists the cheapest ways of playing pitches on the strings not
+// already taken that span no more than maxStretch frets, cheapest first.
+// Leaving every pitch out is always among them.
+func shapes(pitches []int, open [6]int, taken [6]bool) []chordShape {
+	if len(pitches) > len(open) {
+		pitches = pitches[:len(open)] // the rest cannot all be played anyway
+	}
+
+	var found []chordShape
+	current := make([]fretting, len(pitches))
+	var try func(i int, taken [6]bool)
+	try = func(i int, taken [6]bool) {
+		if i == len(pitches) {
+			if shape := newShape(current); shape.high-shape.low <= maxStretch {
+				found = append(found, shape)
+			}
+			return
+		}
+
+		current[i] = fretting{string: -1}
+		try(i+1, taken)
+		for s := range open {
+			fret := pitches[i] - open[s]
+			if taken[s] || fret < 0 || fret > models.MaxFret {
+				continue
+			}
+			current[i] = fretting{string: s, fret: fret}
+			taken[s] = true
+			try(i+1, taken)
+			taken[s] = false
+		}
+	}
+	try(0, taken)
+
+	sort.SliceStable(found, func(a, b int) bool { return found[a].cost < found[b].cost })
+	if len(found) > maxShapes {
+		found = found[:maxShapes]
+	}
+	return found
+}
+
+func newShape(frets []fretting) chordShape {
+	shape := chordShape{frets: append([]fretting(nil), frets...)}
+	for _, f := range frets {
+		switch {
+		case f.string < 0:
+			shape.cost += costUnplaced
+		case f.fret > 0:
+			shape.cost += costFretted
+			if shape.low == 0 || f.fret < shape.low {
+				shape.low = f.fret
+			}
+			shape.high = max(shape.high, f.fret)
+		}
+	}
+	shape.cost += costPosition * shape.low
+	return shape
+}
+
+// handFor returns where the hand is after playing shape from position
+// hand, moving only as far as the shape needs. Position 0 means the hand
+// has not been placed yet, and goes wherever the shape is.
+func (shape chordShape) handFor(hand int) int {
+	switch {
+	case shape.low == 0:
+		return hand
+	case hand == 0:
+		return shape.low
+	}
+	return min(shape.low, max(hand, shape.high-3))
+}
+
+// passageChord is the pitches sounding together at one point of a
+// passage, with the strings already taken by notes whose fret is known.
+type passageChord struct {
+	pitches []int
+	taken   [6]bool
+}
+
+// fingerPassage fingers the chords of a passage in order. It finds the
+// shapes that together cost least, counting what it costs to move the
+// hand between them, by dynamic programming over the hand's position.
+func fingerPassage(chords []passageChord, open [6]int) [][]fretting {
+	const positions = models.MaxFret + 1
+	type step struct{ from, shape int }
+
+	options := make([][]chordShape, len(chords))
+	back := make([][positions]step, len(chords))
+	cost := make([]int, positions)
+	for h := range cost {
+		cost[h] = -1
+	}
+	cost[0] = 0
+
+	for i, chord := range chords {
+		options[i] = shapes(chord.pitches, open, chord.taken)
+		next := make([]int, positions)
+		for h := range next {
+			next[h] = -1
+		}
+		for h, c := range cost {
+			if c < 0 {
+				continue
+			}
+			for j, shape := range options[i] {
+				to := shape.handFor(h)
+				total := c + shape.cost
+				if h > 0 {
+					total += costMove * abs(to-h)
+				}
+				if next[to] < 0 || total < next[to] {
+					next[to] = total
+					back[i][to] = step{from: h, shape: j}
+				}
+			}
+		}
+		cost = next
+	}
+
+	hand := 0
+	for h, c := range cost {
+		if c >= 0 && (cost[hand] < 0 || c < cost[hand]) {
+			hand = h
+		}
+	}
+	fingered := make([][]fretting, len(chords))
+	for i := len(chords) - 1; i >= 0; i-- {
+		s := back[i][hand]
+		if len(options[i]) > 0 {
+			fingered[i] = options[i][s.shape].frets
+		}
+		hand = s.from
+	}
+	return fingered
+}
+
+// fingerNotes gives every note of the measures that has no string yet a
+// string and fret, treating the notes starting at one column as a chord,
+// and drops those that cannot be played. It returns the notes kept and how
+// many were dropped.
+func fingerNotes(measures [][]pitchedNote, open [6]int) ([][]pitchedNote, int) {
+	var columns []int // the measure of each chord
+	var chords []passageChord
+	var unplaced [][]int // indexes of each chord's pitches in its measure
+
+	for m, notes := range measures {
+		sort.SliceStable(notes, func(a, b int) bool { return notes[a].column < notes[b].column })
+		for i := 0; i < len(notes); {
+			j := i
+			for j < len(notes) && notes[j].column == notes[i].column {
+				j++
+			}
+
+			var chord passageChord
+			var indexes []int
+			for k := i; k < j; k++ {
+				if s := notes[k].string; s >= 0 && !chord.taken[s] {
+					chord.taken[s] = true
+					continue
+				}
+				notes[k].string = -1
+				chord.pitches = append(chord.pitches, notes[k].pitch)
+				indexes = append(indexes, k)
+			}
+			if len(chord.pitches) > 0 {
+				columns = append(columns, m)
+				chords = append(chords, chord)
+				unplaced = append(unplaced, indexes)
+			}
+			i = j
+		}
+	}
+
+	for c, frets := range fingerPassage(chords, open) {
+		notes := measures[columns[c]]
+		for k, f := range frets {
+			notes[unplaced[c][k]].string, notes[unplaced[c][k]].fret = f.string, f.fret
+		}
+	}
+
+	dropped := 0
+	kept := make([][]pitchedNote, len(measures))
+	for m, notes := range measures {
+		for _, n := range notes {
+			if n.string < 0 {
+				dropped++
+				continue
+			}
+			kept[m] = append(kept[m], n)
+		}
+	}
+	return kept, dropped
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,753 @@
+// internal/importer/guitarpro.go
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Guitar Pro 3 to 5 files are little-endian binary. Only what tuitar can
+// show is kept; everything else is read past so the stream stays in step.
+// Strings are stored in the Windows code page of the author and are read
+// as Latin-1.
+
+// gpQuarter is the length of a quarter note in ticks.
+const gpQuarter = 960
+
+// ErrGuitarProVersion is returned for files from other Guitar Pro versions
+// than 3, 4 and 5.
+var ErrGuitarProVersion = errors.New("unsupported Guitar Pro version")
+
+type gpSong struct {
+	title       string
+	artist      string
+	tempo       int
+	lyricsTrack int // 1-based; 0 if none
+	lyrics      []string
+	headers     []gpHeader
+	tracks      []gpTrack
+}
+
+type gpHeader struct {
+	numerator   int
+	denominator int
+	marker      string
+}
+
+type gpTrack struct {
+	name       string
+	tuning     []int // MIDI notes, highest string first
+	percussion bool
+	capo       int
+	measures   []gpMeasure
+}
+
+type gpMeasure struct {
+	beats  []gpBeat
+	voice2 bool // the second voice has notes, which tuitar drops
+}
+
+type gpBeat struct {
+	ticks    int
+	empty    bool // takes no time
+	text     string
+	tempo    int // tempo change, 0 if none
+	vibrato  bool
+	harmonic bool // natural harmonics on every note
+	notes    []gpNote
+}
+
+type gpNote struct {
+	string   int // 0 is the highest string
+	fret     int
+	tie      bool
+	dead     bool
+	ghost    bool
+	hammer   bool // hammer-on or pull-off to the next note
+	bend     bool
+	vibrato  bool
+	harmonic bool
+	slide    gpSlide
+}
+
+type gpSlide int
+
+const (
+	slideNone gpSlide = iota
+	slideTo           // to the next note
+	slideOutDown
+	slideOutUp
+	slideInBelow
+	slideInAbove
+)
+
+// gpReader reads the primitives of the format. The first error sticks and
+// turns later reads into no-ops, so callers check it once per structure.
+type gpReader struct {
+	r       *bufio.Reader
+	version int // 300, 400, 500 or 510
+	err     error
+	ignored map[string]int // features tuitar cannot show, by name
+}
+
+func (r *gpReader) read(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("file ends early; it may be truncated")
+		}
+		r.err = err
+	}
+	return buf
+}
+
+func (r *gpReader) skip(n int) {
+	if r.err != nil {
+		return
+	}
+	if _, err := r.r.Discard(n); err != nil {
+		r.err = fmt.Errorf("file ends early; it may be truncated")
+	}
+}
+
+func (r *gpReader) u8() int  { return int(r.read(1)[0]) }
+func (r *gpReader) i8() int  { return int(int8(r.read(1)[0])) }
+func (r *gpReader) i32() int { return int(int32(binary.LittleEndian.Uint32(r.read(4)))) }
+
+// count reads a count and fails on values no real file has, which mean the
+// reader has lost its place.
+func (r *gpReader) count(what string, limit int) int {
+	n := r.i32()
+	if r.err == nil && (n < 0 || n > limit) {
+		r.err = fmt.Errorf("corrupt file: %d %s", n, what)
+		return 0
+	}
+	return n
+}
+
+func (r *gpReader) string(size, length int) string {
+	n := size
+	if n <= 0 {
+		n = length
+	}
+	if length < 0 || n > 1<<16 {
+		if r.err == nil {
+			r.err = fmt.Errorf("corrupt file: string of %d bytes", n)
+		}
+		return ""
+	}
+	buf := r.read(n)
+	if length > n {
+		length = n
+	}
+	return latin1(buf[:length])
+}
+
+// byteSizeString reads a length byte and a field of size bytes.
+func (r *gpReader) byteSizeString(size int) string {
+	return r.string(size, r.u8())
+}
+
+// intSizeString reads a length int and that many bytes.
+func (r *gpReader) intSizeString() string {
+	return r.string(0, r.i32())
+}
+
+// intByteSizeString reads a field size int, then a length byte and the
+// field.
+func (r *gpReader) intByteSizeString() string {
+	size := r.i32() - 1
+	return r.byteSizeString(size)
+}
+
+func (r *gpReader) ignore(feature string) {
+	r.ignored[feature]++
+}
+
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// readGuitarPro reads a whole GP3, GP4 or GP5 file.
+func readGuitarPro(in io.Reader) (*gpSong, map[string]int, error) {
+	r := &gpReader{r: bufio.NewReader(in), ignored: make(map[string]int)}
+
+	if magic, err := r.r.Peek(4); err == nil {
+		switch {
+		case bytes.Equal(magic, []byte("BCFZ")), bytes.Equal(magic, []byte("BCFS")):
+			return nil, nil, fmt.Errorf("%w: Guitar Pro 6 files cannot be read; export them as .gp5", ErrGuitarProVersion)
+		case bytes.Equal(magic, []byte("PK\x03\x04")):
+			return nil, nil, fmt.Errorf("%w: Guitar Pro 7 and later files cannot be read; export them as .gp5", ErrGuitarProVersion)
+		}
+	}
+	if err := r.readVersion(); err != nil {
+		return nil, nil, err
+	}
+
+	song := r.readSong()
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+	return song, r.ignored, nil
+}
+
+func (r *gpReader) readVersion() error {
+	version := r.byteSizeString(30)
+	if r.err != nil {
+		return fmt.Errorf("not a Guitar Pro file")
+	}
+
+	number, ok := strings.CutPrefix(version, "FICHIER GUITAR PRO v")
+	if !ok {
+		if strings.HasPrefix(version, "CLIPBOARD") {
+			return fmt.Errorf("%w: Guitar Pro clipboard files cannot be read", ErrGuitarProVersion)
+		}
+		return fmt.Errorf("not a Guitar Pro file")
+	}
+	major, minor, _ := strings.Cut(number, ".")
+	switch {
+	case major == "3":
+		r.version = 300
+	case major == "4":
+		r.version = 400
+	case major == "5" && minor == "00":
+		r.version = 500
+	case major == "5":
+		r.version = 510
+	default:
+		return fmt.Errorf("%w: %s", ErrGuitarProVersion, version)
+	}
+	return nil
+}
+
+func (r *gpReader) readSong() *gpSong {
+	song := &gpSong{}
+
+	// Title, subtitle, artist, album, words, music (GP5 only), copyright,
+	// tab author and instructions, then the notice lines
+	song.title = r.intByteSizeString()
+	r.intByteSizeString()
+	song.artist = r.intByteSizeString()
+	fields := 5
+	if r.version >= 500 {
+		fields = 6
+	}
+	for i := 0; i < fields; i++ {
+		r.intByteSizeString()
+	}
+	for i := r.count("notice lines", 1000); i > 0; i-- {
+		r.intByteSizeString()
+	}
+
+	if r.version < 500 {
+		r.skip(1) // triplet feel; GP5 has it per measure
+	}
+	if r.version >= 400 {
+		song.lyricsTrack = r.i32()
+		for i := 0; i < 5; i++ {
+			r.skip(4) // starting measure
+			if text := r.intSizeString(); strings.TrimSpace(text) != "" {
+				song.lyrics = append(song.lyrics, text)
+			}
+		}
+	}
+	if r.version >= 510 {
+		r.skip(19) // RSE master effect
+	}
+	if r.version >= 500 {
+		r.skip(30) // page size, margins, proportion, header and footer
+		for i := 0; i < 11; i++ {
+			r.intByteSizeString() // page header and footer texts, tempo name
+		}
+	}
+
+	song.tempo = r.i32()
+	if r.version >= 510 {
+		r.skip(1) // hide tempo
+	}
+	if r.version >= 400 {
+		r.skip(5) // key signature and octave
+	} else {
+		r.skip(4) // key signature
+	}
+	r.skip(64 * 12) // MIDI channels
+	if r.version >= 500 {
+		r.skip(42) // musical directions and master reverb
+	}
+
+	measures := r.count("measures", 10000)
+	tracks := r.count("tracks", 128)
+	if r.err != nil {
+		return nil
+	}
+
+	for i := 0; i < measures; i++ {
+		song.headers = append(song.headers, r.readHeader(i, song.headers))
+	}
+	for i := 0; i < tracks; i++ {
+		song.tracks = append(song.tracks, r.readTrack(i))
+	}
+	if r.version == 500 {
+		r.skip(2)
+	} else if r.version > 500 {
+		r.skip(1)
+	}
+	if r.err != nil {
+		return nil
+	}
+
+	for range song.headers {
+		for t := range song.tracks {
+			track := &song.tracks[t]
+			track.measures = append(track.measures, r.readMeasure(track))
+		}
+		if r.err != nil {
+			return nil
+		}
+	}
+	return song
+}
+
+func (r *gpReader) readHeader(index int, previous []gpHeader) gpHeader {
+	header := gpHeader{numerator: 4, denominator: 4}
+	if n := len(previous); n > 0 {
+		header.numerator, header.denominator = previous[n-1].numerator, previous[n-1].denominator
+	}
+
+	if r.version >= 500 && index > 0 {
+		r.skip(1)
+	}
+	flags := r.u8()
+	if flags&0x01 != 0 {
+		header.numerator = r.i8()
+	}
+	if flags&0x02 != 0 {
+		header.denominator = r.i8()
+	}
+	if flags&0x08 != 0 {
+		r.skip(1) // repeat count
+	}
+
+	if r.version < 500 {
+		if flags&0x10 != 0 {
+			r.skip(1) // alternate ending
+		}
+		if flags&0x20 != 0 {
+			header.marker = r.readMarker()
+		}
+		if flags&0x40 != 0 {
+			r.skip(2) // key signature
+		}
+		return header
+	}
+
+	if flags&0x20 != 0 {
+		header.marker = r.readMarker()
+	}
+	if flags&0x10 != 0 {
+		r.skip(1) // alternate endings
+	}
+	if flags&0x40 != 0 {
+		r.skip(2) // key signature
+	}
+	if flags&0x03 != 0 {
+		r.skip(4) // beam grouping
+	}
+	if flags&0x10 == 0 {
+		r.skip(1)
+	}
+	r.skip(1) // triplet feel
+	return header
+}
+
+func (r *gpReader) readMarker() string {
+	name := r.intByteSizeString()
+	r.skip(4) // color
+	return name
+}
+
+func (r *gpReader) readTrack(index int) gpTrack {
+	var track gpTrack
+
+	if r.version >= 500 && (index == 0 || r.version == 500) {
+		r.skip(1)
+	}
+	flags := r.u8()
+	track.percussion = flags&0x01 != 0
+	track.name = strings.TrimSpace(r.byteSizeString(40))
+
+	count := r.count("strings", 7)
+	for i := 0; i < 7; i++ {
+		note := r.i32()
+		if i < count {
+			track.tuning = append(track.tuning, note)
+		}
+	}
+	r.skip(4) // MIDI port
+	if channel := r.i32(); channel == 10 {
+		track.percussion = true
+	}
+	r.skip(4) // effects channel
+	r.skip(4) // fret count
+	track.capo = r.i32()
+	r.skip(4) // color
+
+	switch {
+	case r.version == 500:
+		r.skip(44) // RSE settings
+	case r.version > 500:
+		r.skip(49)
+		r.intByteSizeString() // RSE effect and effect category
+		r.intByteSizeString()
+	}
+	return track
+}
+
+func (r *gpReader) readMeasure(track *gpTrack) gpMeasure {
+	var measure gpMeasure
+
+	voices := 1
+	if r.version >= 500 {
+		voices = 2
+	}
+	for voice := 0; voice < voices; voice++ {
+		beats := r.count("beats in a measure", 1024)
+		for i := 0; i < beats && r.err == nil; i++ {
+			beat := r.readBeat(track)
+			if voice == 0 {
+				measure.beats = append(measure.beats, beat)
+			} else if len(beat.notes) > 0 {
+				measure.voice2 = true
+			}
+		}
+	}
+	if r.version >= 500 {
+		r.skip(1) // line break
+	}
+	return measure
+}
+
+func (r *gpReader) readBeat(track *gpTrack) gpBeat {
+	var beat gpBeat
+
+	flags := r.u8()
+	if flags&0x40 != 0 {
+		beat.empty = r.u8()&0x02 == 0
+	}
+
+	value := r.i8()
+	if r.err == nil && (value < -2 || value > 6) {
+		r.err = fmt.Errorf("corrupt file: note value %d", value)
+		return beat
+	}
+	beat.ticks = gpQuarter * 4 >> (value + 2)
+	if flags&0x01 != 0 {
+		beat.ticks = beat.ticks * 3 / 2
+	}
+	if flags&0x20 != 0 {
+		enters, times := tupletRatio(r.i32())
+		beat.ticks = beat.ticks * times / enters
+	}
+
+	if flags&0x02 != 0 {
+		r.readChord()
+	}
+	if flags&0x04 != 0 {
+		beat.text = strings.TrimSpace(r.intByteSizeString())
+	}
+	if flags&0x08 != 0 {
+		r.readBeatEffects(&beat)
+	}
+	if flags&0x10 != 0 {
+		r.readMixTable(&beat)
+	}
+
+	played := r.u8() // a bit per string, the highest string at 0x40
+	for i := 6; i >= 0; i-- {
+		if played&(1<<i) != 0 && 6-i < len(track.tuning) {
+			note := r.readNote()
+			note.string = 6 - i
+			beat.notes = append(beat.notes, note)
+		}
+	}
+
+	if r.version >= 500 {
+		r.skip(1)
+		if r.u8()&0x08 != 0 {
+			r.skip(1) // secondary beam break
+		}
+	}
+	return beat
+}
+
+// tupletRatio returns how many notes of a tuplet fill the time of how many
+// plain ones.
+func tupletRatio(tuplet int) (enters, times int) {
+	switch tuplet {
+	case 3:
+		return 3, 2
+	case 5, 6, 7:
+		return tuplet, 4
+	case 9, 10, 11, 12, 13:
+		return tuplet, 8
+	}
+	return 1, 1
+}
+
+// readChord reads past a chord diagram; tuitar keeps chord names in the
+// notes only when they appear as text.
+func (r *gpReader) readChord() {
+	if r.version >= 500 {
+		r.skip(17)
+		r.byteSizeString(21)
+		r.skip(68)
+		return
+	}
+
+	if r.u8()&0x01 == 0 {
+		r.intByteSizeString()
+		if r.i32() != 0 {
+			r.skip(6 * 4)
+		}
+		return
+	}
+	if r.version >= 400 {
+		r.skip(16)
+		r.byteSizeString(21)
+		r.skip(68)
+		return
+	}
+	r.skip(25)
+	r.byteSizeString(34)
+	r.skip(64)
+}
+
+func (r *gpReader) readBeatEffects(beat *gpBeat) {
+	flags1 := r.u8()
+
+	if r.version < 400 {
+		beat.vibrato = flags1&0x03 != 0
+		if flags1&0x20 != 0 {
+			if r.u8() == 0 {
+				r.ignore("tremolo bar dips")
+			} else {
+				r.ignore("taps, slaps and pops")
+			}
+			r.skip(4)
+		}
+		if flags1&0x40 != 0 {
+			r.skip(2)
+			r.ignore("strums")
+		}
+		beat.harmonic = flags1&0x04 != 0
+		if flags1&0x08 != 0 {
+			r.ignore("artificial harmonics")
+		}
+		return
+	}
+
+	flags2 := r.u8()
+	beat.vibrato = flags1&0x02 != 0
+	if flags1&0x20 != 0 {
+		r.skip(1)
+		r.ignore("taps, slaps and pops")
+	}
+	if flags2&0x04 != 0 {
+		r.readBend()
+		r.ignore("tremolo bar dips")
+	}
+	if flags1&0x40 != 0 {
+		r.skip(2)
+		r.ignore("strums")
+	}
+	if flags2&0x02 != 0 {
+		r.skip(1) // pick stroke direction
+	}
+}
+
+func (r *gpReader) readBend() {
+	r.skip(5) // type and value
+	points := r.count("bend points", 100)
+	r.skip(points * 9)
+}
+
+func (r *gpReader) readMixTable(beat *gpBeat) {
+	r.skip(1) // instrument
+	if r.version >= 500 {
+		r.skip(16) // RSE instrument
+	}
+
+	var changes [6]int // volume, balance, chorus, reverb, phaser, tremolo
+	for i := range changes {
+		changes[i] = r.i8()
+	}
+	if r.version >= 500 {
+		r.intByteSizeString() // tempo name
+	}
+	tempo := r.i32()
+
+	for _, change := range changes {
+		if change >= 0 {
+			r.skip(1) // transition length
+		}
+	}
+	if tempo >= 0 {
+		beat.tempo = tempo
+		r.skip(1)
+		if r.version >= 510 {
+			r.skip(1) // hide tempo
+		}
+	}
+	if r.version >= 400 {
+		r.skip(1) // applies to all tracks
+	}
+	if r.version >= 500 {
+		r.skip(1) // wah
+		if r.version >= 510 {
+			r.intByteSizeString() // RSE effect and effect category
+			r.intByteSizeString()
+		}
+	}
+}
+
+func (r *gpReader) readNote() gpNote {
+	var note gpNote
+
+	flags := r.u8()
+	note.ghost = flags&0x04 != 0
+	if flags&0x20 != 0 {
+		kind := r.u8()
+		note.tie = kind == 2
+		note.dead = kind == 3
+	}
+	if r.version < 500 && flags&0x01 != 0 {
+		r.skip(2) // own duration and tuplet
+	}
+	if flags&0x10 != 0 {
+		r.skip(1) // dynamics
+	}
+	if flags&0x20 != 0 {
+		note.fret = r.i8()
+	}
+	if flags&0x80 != 0 {
+		r.skip(2) // fingering
+	}
+	if r.version >= 500 {
+		if flags&0x01 != 0 {
+			r.skip(8) // duration percent
+		}
+		r.skip(1)
+	}
+	if flags&0x08 != 0 {
+		r.readNoteEffects(&note)
+	}
+	return note
+}
+
+func (r *gpReader) readNoteEffects(note *gpNote) {
+	flags1 := r.u8()
+	flags2 := 0
+	if r.version >= 400 {
+		flags2 = r.u8()
+	}
+
+	if flags1&0x01 != 0 {
+		r.readBend()
+		note.bend = true
+	}
+	note.hammer = flags1&0x02 != 0
+	if r.version < 400 && flags1&0x04 != 0 {
+		note.slide = slideTo
+	}
+	if flags1&0x08 != 0 {
+		r.ignore("let ring")
+	}
+	if flags1&0x10 != 0 {
+		if r.version >= 500 {
+			r.skip(5)
+		} else {
+			r.skip(4)
+		}
+		r.ignore("grace notes")
+	}
+	if r.version < 400 {
+		return
+	}
+
+	if flags2&0x02 != 0 {
+		r.ignore("palm mutes")
+	}
+	if flags2&0x04 != 0 {
+		r.skip(1)
+		r.ignore("tremolo picking")
+	}
+	if flags2&0x08 != 0 {
+		note.slide = r.readSlide()
+	}
+	if flags2&0x10 != 0 {
+		kind := r.i8()
+		if r.version >= 500 {
+			switch kind {
+			case 2:
+				r.skip(3)
+			case 3:
+				r.skip(1)
+			}
+		}
+		if kind == 1 {
+			note.harmonic = true
+		} else {
+			r.ignore("artificial harmonics")
+		}
+	}
+	if flags2&0x20 != 0 {
+		r.skip(2)
+		r.ignore("trills")
+	}
+	note.vibrato = flags2&0x40 != 0
+}
+
+func (r *gpReader) readSlide() gpSlide {
+	if r.version < 500 {
+		switch r.i8() {
+		case 1, 2:
+			return slideTo
+		case 3:
+			return slideOutDown
+		case 4:
+			return slideOutUp
+		case -1:
+			return slideInBelow
+		case -2:
+			return slideInAbove
+		}
+		return slideNone
+	}
+
+	flags := r.u8()
+	switch {
+	case flags&0x03 != 0:
+		return slideTo
+	case flags&0x04 != 0:
+		return slideOutDown
+	case flags&0x08 != 0:
+		return slideOutUp
+	case flags&0x10 != 0:
+		return slideInBelow
+	case flags&0x20 != 0:
+		return slideInAbove
+	}
+	return slideNone
+}
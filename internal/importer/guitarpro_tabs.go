@@ -0,0 +1,227 @@
+// internal/importer/guitarpro_tabs.go
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// gpColumn is the length of one tab column, a sixteenth note, in ticks.
+const gpColumn = gpQuarter / 4
+
+// ParseGuitarPro reads a Guitar Pro 3, 4 or 5 file. Every 6-string guitar
+// track becomes a tab, named after the song (or name if the song has no
+// title) and, when there are several, the track. Notes are placed on the
+// sixteenth-note grid of tuitar's columns; markers, beat texts, the capo
+// and lyrics go to the notes. Skipped tracks and effects tuitar cannot
+// show are reported as problems.
+func ParseGuitarPro(r io.Reader, name string) ([]*models.Tab, []Problem, error) {
+	song, ignored, err := readGuitarPro(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var problems []Problem
+	var guitars []int
+	for i, track := range song.tracks {
+		switch {
+		case track.percussion:
+			problems = append(problems, Problem{Reason: fmt.Sprintf("track %d (%s) is a drum track and was skipped", i+1, track.name)})
+		case len(track.tuning) != 6:
+			problems = append(problems, Problem{Reason: fmt.Sprintf("track %d (%s) has %d strings and was skipped; only 6-string tracks can be imported", i+1, track.name, len(track.tuning))})
+		default:
+			guitars = append(guitars, i)
+		}
+	}
+	if len(guitars) == 0 {
+		return nil, problems, fmt.Errorf("no 6-string guitar tracks found")
+	}
+
+	title := strings.TrimSpace(song.title)
+	if title == "" {
+		title = name
+	}
+
+	var tabs []*models.Tab
+	for _, i := range guitars {
+		tab, trackProblems := song.tab(i)
+		tab.Name = title
+		if len(guitars) > 1 && song.tracks[i].name != "" {
+			tab.Name = fmt.Sprintf("%s (%s)", title, song.tracks[i].name)
+		}
+		tabs = append(tabs, tab)
+		problems = append(problems, trackProblems...)
+	}
+
+	features := make([]string, 0, len(ignored))
+	for feature := range ignored {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	for _, feature := range features {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s left out (%d)", feature, ignored[feature])})
+	}
+	return tabs, problems, nil
+}
+
+// tab converts track i of the song.
+func (song *gpSong) tab(i int) (*models.Tab, []Problem) {
+	track := song.tracks[i]
+	tab := models.NewEmptyTab("")
+	tab.Artist = strings.TrimSpace(song.artist)
+	tab.Tempo = song.tempo
+	for s, midi := range track.tuning {
+		tab.Tuning[s], _ = models.PitchName(midi)
+	}
+	// The highest string is written in lower case, as in "e B G D A D"
+	tab.Tuning[0] = strings.ToLower(tab.Tuning[0][:1]) + tab.Tuning[0][1:]
+	if len(song.headers) > 0 {
+		first := song.headers[0]
+		tab.TimeSignature = fmt.Sprintf("%d/%d", first.numerator, first.denominator)
+	}
+
+	var (
+		problems  []Problem
+		notes     []string
+		measures  [][]tabEvent
+		widths    []int
+		pending   legatos
+		rounded   bool
+		voice2    int
+		tempos    = make(map[int]bool)
+		signature = tab.TimeSignature
+	)
+	if track.capo > 0 {
+		notes = append(notes, fmt.Sprintf("Capo %d", track.capo))
+	}
+
+	for m, measure := range track.measures {
+		header := song.headers[m]
+		if header.marker != "" {
+			notes = append(notes, fmt.Sprintf("Bar %d: %s", m+1, header.marker))
+		}
+		if s := fmt.Sprintf("%d/%d", header.numerator, header.denominator); s != signature {
+			problems = append(problems, Problem{Reason: fmt.Sprintf("bar %d: time signature changes to %s; tuitar keeps %s for the whole tab", m+1, s, tab.TimeSignature)})
+			signature = s
+		}
+		if measure.voice2 {
+			voice2++
+		}
+
+		var events []tabEvent
+		tick := 0
+		for b, beat := range measure.beats {
+			if beat.tempo > 0 {
+				if m == 0 && b == 0 {
+					tab.Tempo = beat.tempo
+				} else if beat.tempo != tab.Tempo {
+					tempos[beat.tempo] = true
+				}
+			}
+			if beat.text != "" {
+				notes = append(notes, fmt.Sprintf("Bar %d: %s", m+1, beat.text))
+			}
+			if beat.empty {
+				continue
+			}
+			if tick%gpColumn != 0 || beat.ticks%gpColumn != 0 {
+				rounded = true
+			}
+
+			event := tabEvent{column: (tick + gpColumn/2) / gpColumn}
+			for _, note := range beat.notes {
+				if note.tie {
+					continue
+				}
+				cell := gpNoteCell(note, beat)
+				pending.add(note.string, cell)
+				event.cells[note.string] = cell
+			}
+			events = append(events, event)
+			tick += beat.ticks
+		}
+		measures = append(measures, events)
+
+		width := (tick + gpColumn/2) / gpColumn
+		if width == 0 {
+			width = header.numerator * 16 / max(header.denominator, 1)
+		}
+		widths = append(widths, width)
+	}
+	pending.finish()
+	tab.Content = writeMeasures(measures, widths)
+
+	name := track.name
+	if name == "" {
+		name = fmt.Sprintf("track %d", i+1)
+	}
+	if rounded {
+		problems = append(problems, Problem{Reason: name + ": notes shorter than a sixteenth or in tuplets were moved to the nearest column"})
+	}
+	if voice2 > 0 {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: the second voice of %d %s was left out", name, voice2, models.Plural(voice2, "bar"))})
+	}
+	if len(tempos) > 0 {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: tempo changes left out; the tab keeps %d bpm", name, tab.Tempo)})
+	}
+
+	if len(song.lyrics) > 0 && song.lyricsTrack == i+1 {
+		if len(notes) > 0 {
+			notes = append(notes, "")
+		}
+		notes = append(notes, song.lyrics...)
+	}
+	tab.Notes = strings.Join(notes, "\n")
+	return tab, problems
+}
+
+// gpNoteCell writes a note the way tuitar's tab does: "x" for dead notes,
+// "(5)" for ghost notes, "<12>" for natural harmonics, and technique
+// letters after the fret.
+func gpNoteCell(note gpNote, beat gpBeat) *tabCell {
+	if note.dead {
+		return &tabCell{text: "x"}
+	}
+
+	cell := &tabCell{fret: note.fret, pitch: true}
+	fret := strconv.Itoa(note.fret)
+	switch {
+	case note.harmonic || beat.harmonic:
+		cell.text = "<" + fret + ">"
+	case note.ghost:
+		cell.text = "(" + fret + ")"
+	default:
+		cell.text = fret
+	}
+
+	switch note.slide {
+	case slideInBelow:
+		cell.text = "/" + cell.text
+	case slideInAbove:
+		cell.text = "\\" + cell.text
+	case slideOutUp:
+		cell.suffix = "/"
+	case slideOutDown:
+		cell.suffix = "\\"
+	case slideTo:
+		cell.legato = legatoSlide
+	}
+	if note.hammer {
+		cell.legato = legatoHammer
+	}
+	if note.bend {
+		cell.suffix += "b"
+	}
+	if note.vibrato || beat.vibrato {
+		cell.suffix += "~"
+	}
+	if cell.legato != legatoNone {
+		cell.suffix = " " + cell.suffix // reserved for the legato mark
+	}
+	return cell
+}
@@ -0,0 +1,155 @@
+// internal/importer/guitarpro_test.go
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The fixtures in testdata are written by gen_guitarpro.go, the same song
+// in each version of the format.
+
+var gpProblems = []string{
+	"track 2 (Drums) is a drum track and was skipped",
+	"track 3 (Seven) has 7 strings and was skipped; only 6-string tracks can be imported",
+	"bar 2: time signature changes to 3/4; tuitar keeps 4/4 for the whole tab",
+	"Lead: tempo changes left out; the tab keeps 96 bpm",
+}
+
+func TestParseGuitarPro(t *testing.T) {
+	tests := []struct {
+		file   string
+		lowE   string // the GP3 format has no vibrato on single notes
+		lyrics string
+	}{
+		{file: "song.gp3", lowE: "--------0-------|------------|"},
+		{file: "song.gp4", lowE: "--------0~------|------------|", lyrics: "\n\nla la la"},
+		{file: "song.gp5", lowE: "--------0~------|------------|", lyrics: "\n\nla la la"},
+		{file: "song-510.gp5", lowE: "--------0~------|------------|", lyrics: "\n\nla la la"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			tabs, problems, err := ParseGuitarPro(bytes.NewReader(readFixture(t, tt.file)), tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tabs) != 1 {
+				t.Fatalf("got %d tabs, want 1", len(tabs))
+			}
+			tab := tabs[0]
+
+			if tab.Name != "Fixture Song" || tab.Artist != "Test Band" || tab.Tempo != 96 {
+				t.Errorf("got name %q, artist %q, tempo %d", tab.Name, tab.Artist, tab.Tempo)
+			}
+			if want := [6]string{"e", "B", "G", "D", "A", "D"}; tab.Tuning != want {
+				t.Errorf("got tuning %v, want %v", tab.Tuning, want)
+			}
+
+			content := []string{
+				"3b~-------------|------------|",
+				"----------------|x-----------|",
+				"----2h4---------|------------|",
+				"----------------|------------|",
+				"--------2-------|------------|",
+				tt.lowE,
+			}
+			if strings.Join(tab.Content[:], "\n") != strings.Join(content, "\n") {
+				t.Errorf("got content\n%s\nwant\n%s", strings.Join(tab.Content[:], "\n"), strings.Join(content, "\n"))
+			}
+
+			if want := "Capo 2\nBar 1: Intro\nBar 1: Let ring" + tt.lyrics; tab.Notes != want {
+				t.Errorf("got notes %q, want %q", tab.Notes, want)
+			}
+
+			var got []string
+			for _, p := range problems {
+				got = append(got, p.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(gpProblems, "\n") {
+				t.Errorf("got problems\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(gpProblems, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseGuitarProTruncated(t *testing.T) {
+	for _, file := range []string{"song.gp3", "song.gp4", "song.gp5", "song-510.gp5"} {
+		data := readFixture(t, file)
+		for _, n := range []int{31, 100, len(data) / 2, len(data) - 1} {
+			_, _, err := ParseGuitarPro(bytes.NewReader(data[:n]), file)
+			if err == nil || !strings.Contains(err.Error(), "truncated") {
+				t.Errorf("%s cut to %d bytes: got error %v, want it to say truncated", file, n, err)
+			}
+		}
+	}
+}
+
+func TestParseGuitarProRejected(t *testing.T) {
+	gp3 := readFixture(t, "song.gp3")
+
+	// The count of measures and tracks, right before the first measure header
+	counts := []byte{2, 0, 0, 0, 3, 0, 0, 0, 0x23}
+	tracks := bytes.Index(gp3, counts) + 4
+	if tracks < 4 {
+		t.Fatal("fixture has no track count")
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		version bool // want ErrGuitarProVersion
+		err     string
+	}{
+		{name: "GP6", data: []byte("BCFZ\x00\x00\x00\x00"), version: true, err: "Guitar Pro 6"},
+		{name: "GP6 uncompressed", data: []byte("BCFS\x00\x00\x00\x00"), version: true, err: "Guitar Pro 6"},
+		{name: "GP7", data: []byte("PK\x03\x04\x14\x00\x00\x00"), version: true, err: "Guitar Pro 7"},
+		{name: "clipboard", data: append([]byte{9}, padded("CLIPBOARD", 30)...), version: true, err: "clipboard"},
+		{name: "GP2", data: append([]byte{24}, padded("FICHIER GUITAR PRO v2.21", 30)...), version: true, err: "v2.21"},
+		{name: "text", data: []byte("e|--0--|\nB|--1--|\n"), err: "not a Guitar Pro file"},
+		{name: "title length", data: patch(gp3, 31, []byte{0xff, 0xff, 0xff, 0x7f}), err: "corrupt file: string of"},
+		{name: "track count", data: patch(gp3, tracks, []byte{200, 0, 0, 0}), err: "corrupt file: 200 tracks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseGuitarPro(bytes.NewReader(tt.data), "rejected")
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			if errors.Is(err, ErrGuitarProVersion) != tt.version {
+				t.Errorf("got error %v; wrapping ErrGuitarProVersion: %v, want %v", err, !tt.version, tt.version)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want it to mention %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// padded returns s in a field of size bytes.
+func padded(s string, size int) []byte {
+	field := make([]byte, size)
+	copy(field, s)
+	return field
+}
+
+// patch returns a copy of data with b written at offset.
+func patch(data []byte, offset int, b []byte) []byte {
+	data = bytes.Clone(data)
+	copy(data[offset:], b)
+	return data
+}
@@ -0,0 +1,73 @@
+// internal/importer/importer.go
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// Options are the choices the file being imported cannot make itself.
+type Options struct {
+	// Tuning, highest string first, is what notes read with only a pitch
+	// (MIDI, MusicXML without a tab staff) are fingered for; the zero value
+	// means standard tuning.
+	Tuning [6]string
+}
+
+// ReadFile imports the tabs in the file at path, choosing the format by
+// extension: Guitar Pro files (.gp3, .gp4, .gp5) and MIDI files (.mid,
+// .midi) can hold a tab per track, MusicXML files (.musicxml, .xml,
+// .mxl) a tab per part and ChordPro files (.cho, .chordpro, .chopro, .crd)
+// a tab per song; anything else is read as a plain-text tab. Tabs without a
+// title of their own are named after the file.
+func ReadFile(path string, opts Options) ([]*models.Tab, []Problem, error) {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpx", ".gp":
+		return nil, nil, fmt.Errorf("%w: Guitar Pro 6 and later files cannot be read; export them as .gp5", ErrGuitarProVersion)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gp3", ".gp4", ".gp5":
+		return ParseGuitarPro(f, base)
+	case ".mid", ".midi":
+		return ParseMIDI(f, base, opts)
+	case ".musicxml", ".xml":
+		return ParseMusicXML(f, base, opts)
+	case ".cho", ".chordpro", ".chopro", ".crd":
+		return ParseChordPro(f, base)
+	case ".mxl":
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		score, err := readMXL(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ParseMusicXML(bytes.NewReader(score), base, opts)
+	}
+
+	tab, problems, err := ParseASCII(f)
+	if err != nil {
+		return nil, problems, err
+	}
+
+	if tab.Name == "" {
+		tab.Name = base
+	}
+	return []*models.Tab{tab}, problems, nil
+}
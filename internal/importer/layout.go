@@ -0,0 +1,200 @@
+// internal/importer/layout.go
+package importer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// Formats that store notes with a rhythm rather than as tab text are laid
+// out on tuitar's grid of one column per sixteenth note.
+
+// tabCell is the text of one note in the tab. The suffix of hammer-ons and
+// slides depends on the next note on the string and is set afterwards.
+type tabCell struct {
+	text   string
+	suffix string
+	fret   int
+	pitch  bool // the note has a fret another note can hammer or slide to
+	legato legato
+}
+
+type legato int
+
+const (
+	legatoNone legato = iota
+	legatoHammer
+	legatoSlide
+)
+
+// pitchedNote is a note read with its pitch, placed in its measure in
+// sixteenths.
+type pitchedNote struct {
+	column int
+	pitch  int // sounding MIDI note
+	string int // -1 until known
+	fret   int
+	dead   bool
+	bend   int // semitones; 0 if none
+	harm   bool
+	legato legato
+}
+
+// tabEvent is the notes starting at one column of a measure.
+type tabEvent struct {
+	column int
+	cells  [6]*tabCell
+}
+
+// resolve fills in the legato mark now that the next fret on the string is
+// known; -1 means there is none, and it is taken to go up.
+func (c *tabCell) resolve(next int) {
+	down := next >= 0 && next < c.fret
+	mark := "h"
+	switch {
+	case c.legato == legatoHammer && down:
+		mark = "p"
+	case c.legato == legatoSlide && down:
+		mark = "\\"
+	case c.legato == legatoSlide:
+		mark = "/"
+	}
+	c.suffix = mark + strings.TrimPrefix(c.suffix, " ")
+	c.legato = legatoNone
+}
+
+// legatos holds the last note on each string while its hammer-on,
+// pull-off or slide waits for the note it leads to.
+type legatos [6]*tabCell
+
+// add records cell as the next note on string s, settling the legato mark
+// of the note before it.
+func (l *legatos) add(s int, cell *tabCell) {
+	if prev := l[s]; prev != nil {
+		next := -1
+		if cell.pitch {
+			next = cell.fret
+		}
+		prev.resolve(next)
+	}
+	l[s] = nil
+	if cell.legato != legatoNone {
+		l[s] = cell
+	}
+}
+
+// finish settles the marks still waiting at the end of the tab.
+func (l *legatos) finish() {
+	for _, cell := range l {
+		if cell != nil {
+			cell.resolve(-1)
+		}
+	}
+}
+
+// writeMeasures lays measures out one after another, each measure at least
+// as wide as its entry in widths.
+func writeMeasures(measures [][]tabEvent, widths []int) [6]string {
+	var content [6]strings.Builder
+	for m, events := range measures {
+		writeMeasure(&content, events, widths[m])
+	}
+
+	var lines [6]string
+	for s := range content {
+		lines[s] = content[s].String()
+	}
+	if lines[0] == "" {
+		return models.NewEmptyTab("").Content
+	}
+	return lines
+}
+
+// writeMeasure lays a measure's beats out on the column grid, followed by a
+// bar line. A beat whose text does not fit before the next one pushes the
+// rest of the measure along, and so does a fret that would run into the
+// one before it ("1" then "2" reading as 12).
+func writeMeasure(content *[6]strings.Builder, events []tabEvent, width int) {
+	var lines [6][]byte
+	cursor := 0
+	for _, event := range events {
+		start := max(event.column, cursor)
+		text := 0
+		for s, cell := range event.cells {
+			if cell == nil {
+				continue
+			}
+			text = max(text, len(cell.text)+len(cell.suffix))
+			if len(lines[s]) == start && runsTogether(lines[s], cell.text) {
+				start++
+			}
+		}
+		if text == 0 {
+			continue
+		}
+
+		for s, cell := range event.cells {
+			for len(lines[s]) < start {
+				lines[s] = append(lines[s], '-')
+			}
+			if cell != nil {
+				lines[s] = append(lines[s], cell.text+cell.suffix...)
+			}
+		}
+		cursor = start + text
+	}
+
+	width = max(width, cursor)
+	for s := range lines {
+		for len(lines[s]) < width {
+			lines[s] = append(lines[s], '-')
+		}
+		content[s].Write(lines[s])
+		content[s].WriteByte('|')
+	}
+}
+
+// runsTogether reports whether text written right after line would run
+// into the fret it ends with: "1" then "2" reads as 12, and "12" then "15"
+// is hard to read even where it is not misread.
+func runsTogether(line []byte, text string) bool {
+	return len(line) > 0 && isDigit(line[len(line)-1]) && text != "" && isDigit(text[0])
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// measureEvents turns a measure's notes into tab events, carrying legato
+// marks over to the next note on each string.
+func measureEvents(notes []pitchedNote, pending *legatos) []tabEvent {
+	var events []tabEvent
+	for _, n := range notes {
+		if len(events) == 0 || events[len(events)-1].column != n.column {
+			events = append(events, tabEvent{column: n.column})
+		}
+		event := &events[len(events)-1]
+		if event.cells[n.string] != nil {
+			continue
+		}
+
+		cell := &tabCell{text: "x"}
+		if !n.dead {
+			cell = &tabCell{text: strconv.Itoa(n.fret), fret: n.fret, pitch: true, legato: n.legato}
+			if n.harm {
+				cell.text = "<" + cell.text + ">"
+			}
+			if n.bend != 0 {
+				cell.suffix = "b" + strconv.Itoa(n.fret+n.bend)
+			}
+			if cell.legato != legatoNone {
+				cell.suffix = " " + cell.suffix
+			}
+		}
+		pending.add(n.string, cell)
+		event.cells[n.string] = cell
+	}
+	return events
+}
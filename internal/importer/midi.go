@@ -0,0 +1,250 @@
+// internal/importer/midi.go
+package importer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Standard MIDI files are big-endian chunks: a header and one track of
+// delta-timed events per chunk. Only notes, tempo, time signature, track
+// names, markers and lyrics are kept; everything else is read past.
+
+// midiDrums is the channel General MIDI keeps for percussion, counted
+// from 0.
+const midiDrums = 9
+
+type midiFile struct {
+	format   int
+	division int // ticks per quarter note
+	tracks   []midiTrack
+	tempos   []midiTempo
+	meters   []midiMeter
+	markers  []midiText
+}
+
+type midiTrack struct {
+	name   string
+	notes  []midiNote
+	lyrics []midiText
+}
+
+type midiNote struct {
+	start, end int // in ticks
+	pitch      int
+	channel    int
+}
+
+type midiTempo struct {
+	tick int
+	bpm  float64
+}
+
+type midiMeter struct {
+	tick        int
+	numerator   int
+	denominator int
+}
+
+type midiText struct {
+	tick int
+	text string
+}
+
+// midiReader reads the events of a track chunk. The first error sticks,
+// as with gpReader.
+type midiReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// read returns the next n bytes. Once the track has run out it returns a
+// single zero byte instead, so that a corrupt length never allocates what
+// it claims; the error tells the caller to drop what was read.
+func (r *midiReader) read(n int) []byte {
+	if r.err == nil && (n < 0 || n > len(r.data)-r.pos) {
+		r.err = fmt.Errorf("track ends early; the file may be truncated")
+	}
+	if r.err != nil {
+		return []byte{0}
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *midiReader) u8() int { return int(r.read(1)[0]) }
+
+// vlq reads a variable-length quantity: seven bits a byte, the high bit
+// set on all but the last.
+func (r *midiReader) vlq() int {
+	n := 0
+	for i := 0; i < 4; i++ {
+		b := r.u8()
+		n = n<<7 | b&0x7f
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return n
+}
+
+// readMIDI reads a standard MIDI file.
+func readMIDI(r io.Reader) (*midiFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 14 || string(data[:4]) != "MThd" {
+		return nil, fmt.Errorf("not a MIDI file")
+	}
+
+	size := int(binary.BigEndian.Uint32(data[4:8]))
+	file := &midiFile{
+		format:   int(binary.BigEndian.Uint16(data[8:10])),
+		division: int(binary.BigEndian.Uint16(data[12:14])),
+	}
+	switch {
+	case file.format > 1:
+		return nil, fmt.Errorf("MIDI format %d files cannot be read; save as format 0 or 1", file.format)
+	case file.division&0x8000 != 0:
+		return nil, fmt.Errorf("MIDI files timed in SMPTE frames cannot be read")
+	case file.division == 0:
+		return nil, fmt.Errorf("MIDI file has no ticks per quarter note")
+	}
+
+	pos := 8 + size
+	for pos+8 <= len(data) {
+		id := string(data[pos : pos+4])
+		size := int(binary.BigEndian.Uint32(data[pos+4 : pos+8]))
+		pos += 8
+		end := min(pos+size, len(data))
+		if id == "MTrk" {
+			if err := file.readTrack(data[pos:end]); err != nil {
+				return nil, fmt.Errorf("track %d: %w", len(file.tracks)+1, err)
+			}
+		}
+		pos = end
+	}
+	if len(file.tracks) == 0 {
+		return nil, fmt.Errorf("MIDI file has no tracks")
+	}
+
+	sort.SliceStable(file.tempos, func(a, b int) bool { return file.tempos[a].tick < file.tempos[b].tick })
+	sort.SliceStable(file.meters, func(a, b int) bool { return file.meters[a].tick < file.meters[b].tick })
+	sort.SliceStable(file.markers, func(a, b int) bool { return file.markers[a].tick < file.markers[b].tick })
+	return file, nil
+}
+
+// readTrack reads the events of a track chunk. Notes still sounding at the
+// end of the track end there.
+func (file *midiFile) readTrack(data []byte) error {
+	r := &midiReader{data: data}
+	var track midiTrack
+	sounding := make(map[[2]int][]int) // start ticks of the notes on, by channel and pitch
+	tick, status := 0, 0
+
+	noteOff := func(channel, pitch int) {
+		key := [2]int{channel, pitch}
+		starts := sounding[key]
+		if len(starts) == 0 {
+			return
+		}
+		track.notes = append(track.notes, midiNote{start: starts[0], end: tick, pitch: pitch, channel: channel})
+		sounding[key] = starts[1:]
+	}
+
+events:
+	for r.pos < len(r.data) && r.err == nil {
+		tick += r.vlq()
+		b := r.u8()
+		if b&0x80 != 0 {
+			if b < 0xf0 {
+				status = b // only channel messages set the running status
+			}
+		} else {
+			r.pos-- // running status: b is the first data byte
+			b = status
+		}
+
+		switch {
+		case b == 0xff:
+			kind := r.u8()
+			data := r.read(r.vlq())
+			switch kind {
+			case 0x03:
+				if track.name == "" {
+					track.name = trimText(data)
+				}
+			case 0x05:
+				track.lyrics = append(track.lyrics, midiText{tick, latin1(data)})
+			case 0x06:
+				if text := trimText(data); text != "" {
+					file.markers = append(file.markers, midiText{tick, text})
+				}
+			case 0x2f:
+				break events
+			case 0x51:
+				if len(data) == 3 {
+					if us := int(data[0])<<16 | int(data[1])<<8 | int(data[2]); us > 0 {
+						file.tempos = append(file.tempos, midiTempo{tick, 60e6 / float64(us)})
+					}
+				}
+			case 0x58:
+				if len(data) >= 2 && data[1] < 8 {
+					file.meters = append(file.meters, midiMeter{tick, int(data[0]), 1 << data[1]})
+				}
+			}
+		case b == 0xf0 || b == 0xf7:
+			r.read(r.vlq())
+		case b < 0x80:
+			return fmt.Errorf("data byte with no status at tick %d", tick)
+		default:
+			channel := b & 0x0f
+			switch b & 0xf0 {
+			case 0x80:
+				pitch := r.u8()
+				r.u8()
+				noteOff(channel, pitch)
+			case 0x90:
+				pitch, velocity := r.u8(), r.u8()
+				if velocity == 0 {
+					noteOff(channel, pitch)
+					continue
+				}
+				key := [2]int{channel, pitch}
+				sounding[key] = append(sounding[key], tick)
+			case 0xc0, 0xd0:
+				r.u8()
+			default:
+				r.read(2)
+			}
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	for key, starts := range sounding {
+		for range starts {
+			noteOff(key[0], key[1])
+		}
+	}
+	sort.SliceStable(track.notes, func(a, b int) bool {
+		if track.notes[a].start != track.notes[b].start {
+			return track.notes[a].start < track.notes[b].start
+		}
+		return track.notes[a].pitch > track.notes[b].pitch
+	})
+	file.tracks = append(file.tracks, track)
+	return nil
+}
+
+// trimText reads a text event, which has no set encoding, as Latin-1.
+func trimText(data []byte) string {
+	return strings.TrimSpace(latin1(data))
+}
@@ -0,0 +1,214 @@
+// internal/importer/midi_tabs.go
+package importer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// midiVoice is the notes of one channel of a track, which becomes a tab.
+type midiVoice struct {
+	label  string
+	notes  []midiNote
+	lyrics []midiText
+}
+
+// ParseMIDI reads a standard MIDI file. Every channel of every track with
+// notes becomes a tab, except for drums, named after the first track (or
+// name if it has none) and, when there are several, the track. Notes are
+// moved to the nearest column of the sixteenth-note grid and given a
+// string and fret in opts.Tuning by fingerNotes; markers and lyrics go to
+// the notes.
+func ParseMIDI(r io.Reader, name string, opts Options) ([]*models.Tab, []Problem, error) {
+	file, err := readMIDI(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	title := name
+	tracks := file.tracks
+	if first := tracks[0]; first.name != "" && (file.format == 0 || len(first.notes) == 0) {
+		title = first.name
+	}
+	if file.format == 1 && len(tracks[0].notes) == 0 {
+		tracks = tracks[1:] // the tempo track
+	}
+
+	var problems []Problem
+	var voices []midiVoice
+	for i, track := range tracks {
+		label := track.name
+		if label == "" || (file.format == 0 && label == title) {
+			label = fmt.Sprintf("track %d", i+1)
+		}
+
+		byChannel := make(map[int][]midiNote)
+		var channels []int
+		for _, n := range track.notes {
+			if _, ok := byChannel[n.channel]; !ok {
+				channels = append(channels, n.channel)
+			}
+			byChannel[n.channel] = append(byChannel[n.channel], n)
+		}
+		for c, channel := range channels {
+			voice := midiVoice{label: label, notes: byChannel[channel]}
+			if len(channels) > 1 {
+				voice.label = fmt.Sprintf("%s, channel %d", label, channel+1)
+			}
+			if c == 0 {
+				voice.lyrics = track.lyrics
+			}
+			if channel == midiDrums {
+				problems = append(problems, Problem{Reason: fmt.Sprintf("%s is a drum track and was skipped", voice.label)})
+				continue
+			}
+			voices = append(voices, voice)
+		}
+	}
+	if len(voices) == 0 {
+		return nil, problems, fmt.Errorf("no tracks with notes found")
+	}
+
+	var tabs []*models.Tab
+	for _, voice := range voices {
+		tab, voiceProblems := file.tab(voice, opts.Tuning)
+		tab.Name = title
+		if len(voices) > 1 {
+			tab.Name = fmt.Sprintf("%s (%s)", title, voice.label)
+		}
+		tabs = append(tabs, tab)
+		problems = append(problems, voiceProblems...)
+	}
+	return tabs, problems, nil
+}
+
+// tab converts a voice of the file, fingered for tuning; a zero tuning is
+// standard tuning.
+func (file *midiFile) tab(voice midiVoice, tuning [6]string) (*models.Tab, []Problem) {
+	tab := models.NewEmptyTab("")
+	if tuning != ([6]string{}) {
+		tab.Tuning = tuning
+	}
+	var problems []Problem
+
+	if len(file.tempos) > 0 {
+		tab.Tempo = int(math.Round(file.tempos[0].bpm))
+		for _, t := range file.tempos[1:] {
+			if int(math.Round(t.bpm)) != tab.Tempo {
+				problems = append(problems, Problem{Reason: fmt.Sprintf("%s: tempo changes left out; the tab keeps %d bpm", voice.label, tab.Tempo)})
+				break
+			}
+		}
+	}
+	if len(file.meters) > 0 {
+		first := file.meters[0]
+		tab.TimeSignature = fmt.Sprintf("%d/%d", first.numerator, first.denominator)
+		for _, m := range file.meters[1:] {
+			if sig := fmt.Sprintf("%d/%d", m.numerator, m.denominator); sig != tab.TimeSignature {
+				problems = append(problems, Problem{Reason: fmt.Sprintf("%s, bar %d: time signature changes to %s; tuitar keeps %s for the whole tab", voice.label, file.bar(tab, m.tick), sig, tab.TimeSignature)})
+				break
+			}
+		}
+	}
+
+	perMeasure := tab.ColumnsPerMeasure()
+	last, rounded := 0, 0
+	for _, n := range voice.notes {
+		last = max(last, file.column(n.start), file.column(n.end)-1)
+	}
+	measures := make([][]pitchedNote, last/perMeasure+1)
+	seen := make(map[[2]int]bool) // column and pitch of the notes kept
+	for _, n := range voice.notes {
+		column := file.column(n.start)
+		if n.start*models.ColumnsPerBeat%file.division != 0 {
+			rounded++
+		}
+		if seen[[2]int{column, n.pitch}] {
+			continue
+		}
+		seen[[2]int{column, n.pitch}] = true
+		m := column / perMeasure
+		measures[m] = append(measures[m], pitchedNote{column: column - m*perMeasure, pitch: n.pitch, string: -1})
+	}
+
+	measures, unplayable := fingerNotes(measures, tab.OpenStrings())
+	var pending legatos
+	events := make([][]tabEvent, len(measures))
+	widths := make([]int, len(measures))
+	for m, notes := range measures {
+		events[m] = measureEvents(notes, &pending)
+		widths[m] = perMeasure
+	}
+	tab.Content = writeMeasures(events, widths)
+
+	if rounded > 0 {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: %d %s off the sixteenth-note grid moved to the nearest column", voice.label, rounded, models.Plural(rounded, "note"))})
+	}
+	if unplayable > 0 {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: %d %s out of the guitar's range, with no free string or beyond a %d-fret stretch left out", voice.label, unplayable, models.Plural(unplayable, "note"), maxStretch)})
+	}
+
+	var notes []string
+	for _, marker := range file.markers {
+		notes = append(notes, fmt.Sprintf("Bar %d: %s", file.bar(tab, marker.tick), marker.text))
+	}
+	if lyrics := midiLyrics(voice.lyrics); len(lyrics) > 0 {
+		if len(notes) > 0 {
+			notes = append(notes, "")
+		}
+		notes = append(notes, models.WrapWords(lyrics, 60)...)
+	}
+	tab.Notes = strings.Join(notes, "\n")
+	return tab, problems
+}
+
+// column returns the tab column nearest tick.
+func (file *midiFile) column(tick int) int {
+	return (tick*models.ColumnsPerBeat + file.division/2) / file.division
+}
+
+// bar returns the 1-based measure of the tab tick falls in.
+func (file *midiFile) bar(tab *models.Tab, tick int) int {
+	return file.column(tick)/tab.ColumnsPerMeasure() + 1
+}
+
+// midiLyrics joins lyric events into words. Files that mark where words
+// end, with spaces, line breaks or a "-" carrying a syllable over, have
+// their syllables joined accordingly; in files that mark nothing every
+// event is taken to be a word.
+func midiLyrics(events []midiText) []string {
+	marked := false
+	for _, e := range events {
+		if strings.ContainsAny(e.text, " -/\\\r\n") {
+			marked = true
+			break
+		}
+	}
+
+	var words []string
+	join := false // the next syllable continues the last word
+	for _, e := range events {
+		text := strings.TrimLeft(e.text, " /\\\r\n")
+		if text != e.text {
+			join = false
+		}
+		word := strings.TrimRight(text, " \r\n")
+		ends := word != text
+		word = strings.TrimSuffix(word, "-")
+		switch {
+		case word == "":
+			join = false
+			continue
+		case join && len(words) > 0:
+			words[len(words)-1] += word
+		default:
+			words = append(words, word)
+		}
+		join = marked && !ends
+	}
+	return words
+}
@@ -0,0 +1,531 @@
+// internal/importer/musicxml.go
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// MusicXML is read from partwise scores, plain (.musicxml, .xml) or
+// compressed (.mxl). Notes on a TAB staff keep their string and fret;
+// notes that only have a pitch are given one by fingerNotes.
+
+type mxlScore struct {
+	XMLName       xml.Name
+	Title         string `xml:"work>work-title"`
+	MovementTitle string `xml:"movement-title"`
+	Creators      []struct {
+		Type string `xml:"type,attr"`
+		Name string `xml:",chardata"`
+	} `xml:"identification>creator"`
+	PartNames []struct {
+		ID   string `xml:"id,attr"`
+		Name string `xml:"part-name"`
+	} `xml:"part-list>score-part"`
+	Parts []mxlPart `xml:"part"`
+}
+
+type mxlPart struct {
+	ID       string       `xml:"id,attr"`
+	Measures []mxlMeasure `xml:"measure"`
+}
+
+type mxlMeasure struct {
+	Number string    `xml:"number,attr"`
+	Items  []mxlItem `xml:",any"`
+}
+
+// mxlItem is any element of a measure; which fields are set depends on
+// the element, named by XMLName. Keeping them in one list keeps their
+// order, which places notes in time.
+type mxlItem struct {
+	XMLName xml.Name
+
+	// note, backup and forward
+	Chord    *struct{} `xml:"chord"`
+	Grace    *struct{} `xml:"grace"`
+	Rest     *struct{} `xml:"rest"`
+	Pitch    *mxlPitch `xml:"pitch"`
+	Duration int       `xml:"duration"`
+	Ties     []mxlType `xml:"tie"`
+	Staff    int       `xml:"staff"`
+	Notehead string    `xml:"notehead"`
+	String   int       `xml:"notations>technical>string"`
+	Fret     *int      `xml:"notations>technical>fret"`
+	HammerOn []mxlType `xml:"notations>technical>hammer-on"`
+	PullOff  []mxlType `xml:"notations>technical>pull-off"`
+	Bend     []string  `xml:"notations>technical>bend>bend-alter"`
+	Harmonic *struct{} `xml:"notations>technical>harmonic"`
+	Slides   []mxlType `xml:"notations>slide"`
+	Glissand []mxlType `xml:"notations>glissando"`
+	Lyrics   []struct {
+		Number   string `xml:"number,attr"`
+		Syllabic string `xml:"syllabic"`
+		Text     string `xml:"text"`
+	} `xml:"lyric"`
+
+	// attributes
+	Divisions int    `xml:"divisions"`
+	Beats     string `xml:"time>beats"`
+	BeatType  string `xml:"time>beat-type"`
+	Clefs     []struct {
+		Number int    `xml:"number,attr"`
+		Sign   string `xml:"sign"`
+	} `xml:"clef"`
+	StaffDetails []struct {
+		Number int `xml:"number,attr"`
+		Lines  int `xml:"staff-lines"`
+		Tuning []struct {
+			Line   int     `xml:"line,attr"`
+			Step   string  `xml:"tuning-step"`
+			Alter  float64 `xml:"tuning-alter"`
+			Octave int     `xml:"tuning-octave"`
+		} `xml:"staff-tuning"`
+		Capo int `xml:"capo"`
+	} `xml:"staff-details"`
+	Chromatic    *int `xml:"transpose>chromatic"`
+	OctaveChange int  `xml:"transpose>octave-change"`
+
+	// direction and sound
+	Tempo float64 `xml:"tempo,attr"`
+	Sound *struct {
+		Tempo float64 `xml:"tempo,attr"`
+	} `xml:"sound"`
+	PerMinute string   `xml:"direction-type>metronome>per-minute"`
+	Words     []string `xml:"direction-type>words"`
+	Rehearsal []string `xml:"direction-type>rehearsal"`
+}
+
+type mxlPitch struct {
+	Step   string  `xml:"step"`
+	Alter  float64 `xml:"alter"`
+	Octave int     `xml:"octave"`
+}
+
+type mxlType struct {
+	Type string `xml:"type,attr"`
+}
+
+// midi returns the MIDI note of a written pitch.
+func (p mxlPitch) midi() int {
+	pc, _ := models.ParsePitchClass(p.Step)
+	return (p.Octave+1)*12 + pc + int(math.Round(p.Alter))
+}
+
+// ParseMusicXML reads a MusicXML score. Every part with a 6-line TAB staff
+// becomes a tab, named after the work (or name if it has none) and, when
+// there are several, the part. A score without tab staves gets a tab of its
+// first part in opts.Tuning, with strings and frets chosen for it. Text
+// directions and rehearsal marks go to the notes, and so do the lyrics.
+func ParseMusicXML(r io.Reader, name string, opts Options) ([]*models.Tab, []Problem, error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charsetReader
+	dec.Entity = xml.HTMLEntity
+
+	var score mxlScore
+	if err := dec.Decode(&score); err != nil {
+		return nil, nil, fmt.Errorf("not a MusicXML file: %w", err)
+	}
+	switch score.XMLName.Local {
+	case "score-partwise":
+	case "score-timewise":
+		return nil, nil, fmt.Errorf("timewise MusicXML scores are not supported; save the score as partwise")
+	default:
+		return nil, nil, fmt.Errorf("not a MusicXML score: root element is <%s>", score.XMLName.Local)
+	}
+
+	title := strings.TrimSpace(score.Title)
+	if title == "" {
+		title = strings.TrimSpace(score.MovementTitle)
+	}
+	if title == "" {
+		title = name
+	}
+	var artist string
+	for _, c := range score.Creators {
+		if c.Type == "composer" || c.Type == "artist" {
+			artist = strings.TrimSpace(c.Name)
+			break
+		}
+	}
+	partNames := make(map[string]string)
+	for _, p := range score.PartNames {
+		partNames[p.ID] = strings.TrimSpace(p.Name)
+	}
+
+	var problems []Problem
+	var tabs []*models.Tab
+	var tabParts []string
+	for i, part := range score.Parts {
+		label := partNames[part.ID]
+		if label == "" {
+			label = fmt.Sprintf("part %d", i+1)
+		}
+		staff, lines := tabStaff(part)
+		switch {
+		case staff == 0:
+			continue
+		case lines != 6:
+			problems = append(problems, Problem{Reason: fmt.Sprintf("%s has a %d-line tab staff and was skipped; only 6-string tabs can be imported", label, lines)})
+			continue
+		}
+		tab, partProblems := partTab(part, label, staff, opts.Tuning)
+		tabs = append(tabs, tab)
+		tabParts = append(tabParts, partNames[part.ID])
+		problems = append(problems, partProblems...)
+	}
+
+	if len(tabs) == 0 {
+		if len(score.Parts) == 0 {
+			return nil, problems, fmt.Errorf("the score has no parts")
+		}
+		label := partNames[score.Parts[0].ID]
+		if label == "" {
+			label = "part 1"
+		}
+		tab, partProblems := partTab(score.Parts[0], label, 0, opts.Tuning)
+		tabs = append(tabs, tab)
+		tabParts = append(tabParts, partNames[score.Parts[0].ID])
+		problems = append(problems, partProblems...)
+		if n := len(score.Parts) - 1; n > 0 {
+			problems = append(problems, Problem{Reason: fmt.Sprintf("the score has no tab staves; only %s was imported; %d other %s skipped", label, n, models.Plural(n, "part"))})
+		}
+	}
+
+	for i, tab := range tabs {
+		tab.Name = title
+		if len(tabs) > 1 && tabParts[i] != "" {
+			tab.Name = fmt.Sprintf("%s (%s)", title, tabParts[i])
+		}
+		tab.Artist = artist
+	}
+	return tabs, problems, nil
+}
+
+// tabStaff returns the number of the part's TAB staff and its line count,
+// or 0 if it has none.
+func tabStaff(part mxlPart) (staff, lines int) {
+	for _, measure := range part.Measures {
+		for _, item := range measure.Items {
+			if item.XMLName.Local != "attributes" {
+				continue
+			}
+			for _, clef := range item.Clefs {
+				if strings.EqualFold(clef.Sign, "TAB") {
+					staff = max(clef.Number, 1)
+				}
+			}
+			if staff == 0 {
+				continue
+			}
+			lines = 6
+			for _, details := range item.StaffDetails {
+				if max(details.Number, 1) == staff && details.Lines > 0 {
+					lines = details.Lines
+				}
+			}
+			return staff, lines
+		}
+	}
+	return 0, 0
+}
+
+// partTab converts a part. With a tab staff, only its notes are read and
+// they keep their strings and frets, and the staff's own tuning replaces
+// tuning; staff 0 means the part has none, and the notes of its first
+// staff are fingered for tuning. A zero tuning is standard tuning.
+func partTab(part mxlPart, label string, staff int, tuning [6]string) (*models.Tab, []Problem) {
+	tab := models.NewEmptyTab("")
+	if tuning != ([6]string{}) {
+		tab.Tuning = tuning
+	}
+	open := tab.OpenStrings()
+
+	var (
+		problems   []Problem
+		notes      []string
+		lyrics     []string
+		syllable   bool // the last lyric ended mid-word
+		measures   [][]pitchedNote
+		widths     []int
+		pending    legatos
+		divisions  = 1
+		transpose  = 0
+		haveTempo  bool
+		haveTime   bool
+		tempoDrops bool
+		rounded    bool
+		graces     int
+		otherStaff int
+	)
+
+	toColumn := func(pos int) int {
+		cols := pos * models.ColumnsPerBeat
+		if cols%divisions != 0 {
+			rounded = true
+		}
+		return (cols + divisions/2) / divisions
+	}
+	setTempo := func(tempo float64) {
+		switch {
+		case tempo <= 0:
+		case !haveTempo:
+			tab.Tempo = int(math.Round(tempo))
+			haveTempo = true
+		case int(math.Round(tempo)) != tab.Tempo:
+			tempoDrops = true
+		}
+	}
+
+	for m, measure := range part.Measures {
+		var placed []pitchedNote
+		pos, end, last := 0, 0, 0 // last is where the previous note started
+
+		for _, item := range measure.Items {
+			switch item.XMLName.Local {
+			case "attributes":
+				if item.Divisions > 0 {
+					divisions = item.Divisions
+				}
+				if item.Beats != "" {
+					sig := item.Beats + "/" + item.BeatType
+					if !haveTime {
+						tab.TimeSignature = sig
+						haveTime = true
+					} else if sig != tab.TimeSignature {
+						problems = append(problems, Problem{Reason: fmt.Sprintf("%s, bar %d: time signature changes to %s; tuitar keeps %s for the whole tab", label, m+1, sig, tab.TimeSignature)})
+					}
+				}
+				for _, details := range item.StaffDetails {
+					if max(details.Number, 1) != max(staff, 1) {
+						continue
+					}
+					if staff > 0 && len(details.Tuning) == 6 {
+						for _, t := range details.Tuning {
+							if t.Line >= 1 && t.Line <= 6 {
+								open[6-t.Line] = mxlPitch{Step: t.Step, Alter: t.Alter, Octave: t.Octave}.midi()
+							}
+						}
+					}
+					if details.Capo > 0 {
+						notes = append(notes, fmt.Sprintf("Capo %d", details.Capo))
+					}
+				}
+				if item.Chromatic != nil {
+					transpose = *item.Chromatic + 12*item.OctaveChange
+				}
+
+			case "backup":
+				pos = max(pos-item.Duration, 0)
+			case "forward":
+				pos += item.Duration
+				end = max(end, pos)
+
+			case "sound":
+				setTempo(item.Tempo)
+			case "direction":
+				if item.Sound != nil {
+					setTempo(item.Sound.Tempo)
+				}
+				if tempo, err := strconv.ParseFloat(strings.TrimSpace(item.PerMinute), 64); err == nil {
+					setTempo(tempo)
+				}
+				for _, text := range append(item.Rehearsal, item.Words...) {
+					if text = strings.TrimSpace(text); text != "" {
+						notes = append(notes, fmt.Sprintf("Bar %d: %s", m+1, text))
+					}
+				}
+
+			case "note":
+				if item.Grace != nil {
+					graces++
+					continue
+				}
+				start := pos
+				if item.Chord != nil {
+					start = last
+				} else {
+					last = pos
+					pos += item.Duration
+					end = max(end, pos)
+				}
+
+				if max(item.Staff, 1) != max(staff, 1) {
+					// A tab staff's notes repeat those of the staff above it
+					if staff == 0 && item.Rest == nil {
+						otherStaff++
+					}
+					continue
+				}
+				for _, l := range item.Lyrics {
+					if (l.Number == "" || l.Number == "1") && strings.TrimSpace(l.Text) != "" {
+						text := strings.TrimSpace(l.Text)
+						if syllable && len(lyrics) > 0 {
+							lyrics[len(lyrics)-1] += text
+						} else {
+							lyrics = append(lyrics, text)
+						}
+						syllable = l.Syllabic == "begin" || l.Syllabic == "middle"
+					}
+				}
+				if item.Rest != nil || item.Pitch == nil || isTieStop(item.Ties) {
+					continue
+				}
+
+				note := pitchedNote{
+					column: toColumn(start),
+					pitch:  item.Pitch.midi() + transpose,
+					string: -1,
+					dead:   item.Notehead == "x",
+					bend:   bendAlter(item.Bend),
+					harm:   item.Harmonic != nil,
+				}
+				if staff > 0 && item.Fret != nil && item.String >= 1 && item.String <= 6 {
+					note.string, note.fret = item.String-1, *item.Fret
+				}
+				switch {
+				case hasStart(item.HammerOn), hasStart(item.PullOff):
+					note.legato = legatoHammer
+				case hasStart(item.Slides), hasStart(item.Glissand):
+					note.legato = legatoSlide
+				}
+				placed = append(placed, note)
+			}
+		}
+
+		measures = append(measures, placed)
+		width := toColumn(end)
+		if width == 0 {
+			width = tab.ColumnsPerMeasure()
+		}
+		widths = append(widths, width)
+	}
+	measures, unplayable := fingerNotes(measures, open)
+	events := make([][]tabEvent, len(measures))
+	for m, notes := range measures {
+		events[m] = measureEvents(notes, &pending)
+	}
+	pending.finish()
+	tab.Content = writeMeasures(events, widths)
+
+	if open != tab.OpenStrings() {
+		for i, midi := range open {
+			name, _ := models.PitchName(midi)
+			tab.Tuning[i] = name
+		}
+		tab.Tuning[0] = strings.ToLower(tab.Tuning[0][:1]) + tab.Tuning[0][1:]
+	}
+
+	if rounded {
+		problems = append(problems, Problem{Reason: label + ": notes shorter than a sixteenth or in tuplets were moved to the nearest column"})
+	}
+	if graces > 0 {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: grace notes left out (%d)", label, graces)})
+	}
+	if unplayable > 0 {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: %d %s out of the guitar's range, with no free string or beyond a %d-fret stretch left out", label, unplayable, models.Plural(unplayable, "note"), maxStretch)})
+	}
+	if otherStaff > 0 {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: %d %s on other staves left out", label, otherStaff, models.Plural(otherStaff, "note"))})
+	}
+	if tempoDrops {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("%s: tempo changes left out; the tab keeps %d bpm", label, tab.Tempo)})
+	}
+
+	if len(lyrics) > 0 {
+		if len(notes) > 0 {
+			notes = append(notes, "")
+		}
+		notes = append(notes, models.WrapWords(lyrics, 60)...)
+	}
+	tab.Notes = strings.Join(notes, "\n")
+	return tab, problems
+}
+
+// bendAlter returns how far the first bend of a note goes, in semitones.
+func bendAlter(alters []string) int {
+	if len(alters) == 0 {
+		return 0
+	}
+	alter, err := strconv.ParseFloat(strings.TrimSpace(alters[0]), 64)
+	if err != nil || alter == 0 {
+		return 2
+	}
+	return int(math.Round(alter))
+}
+
+func isTieStop(ties []mxlType) bool {
+	for _, t := range ties {
+		if t.Type == "stop" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasStart(marks []mxlType) bool {
+	for _, m := range marks {
+		if m.Type == "start" {
+			return true
+		}
+	}
+	return false
+}
+
+// readMXL returns the score inside a compressed MusicXML file, named by
+// its container file or else the first score in the archive.
+func readMXL(data []byte) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a compressed MusicXML file: %w", err)
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			Path string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	open := func(name string) ([]byte, error) {
+		f, err := archive.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+
+	if data, err := open("META-INF/container.xml"); err == nil {
+		if xml.Unmarshal(data, &container) == nil && len(container.Rootfiles) > 0 {
+			return open(container.Rootfiles[0].Path)
+		}
+	}
+	for _, f := range archive.File {
+		ext := strings.ToLower(path.Ext(f.Name))
+		if !strings.HasPrefix(f.Name, "META-INF/") && (ext == ".xml" || ext == ".musicxml") {
+			return open(f.Name)
+		}
+	}
+	return nil, fmt.Errorf("no score found in the compressed MusicXML file")
+}
+
+// charsetReader lets the decoder read scores saved in Latin-1.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252", "us-ascii":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(latin1(data)), nil
+	}
+	return nil, fmt.Errorf("unsupported character encoding %s", charset)
+}
@@ -2,7 +2,7 @@
 package midi
 
 import (
-	"strconv"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,9 +16,20 @@ type Player struct {
 	tempo        int
 	notes        []PlayableNote
 	highlighted  []models.Position
-	stopChan     chan bool
+	stopChan     chan struct{} // closed by Stop; each run has its own
 	currentTab   *models.Tab
 	playbackTime time.Duration
+	songs        []Song
+	song         int // index into songs of the current tab
+	gap          time.Duration
+}
+
+// Song is one tab of a continuous playback, sounding Transpose semitones
+// above its written pitch at Tempo, or the tab's own tempo if 0.
+type Song struct {
+	Tab       *models.Tab
+	Transpose int
+	Tempo     int
 }
 
 type PlayableNote struct {
@@ -32,32 +43,35 @@ type PlayableNote struct {
 
 func NewPlayer() *Player {
 	return &Player{
-		tempo:    120,
-		stopChan: make(chan bool, 1),
+		tempo: 120,
 	}
 }
 
 func (p *Player) PlayTab(tab *models.Tab) error {
+	return p.PlaySetlist([]Song{{Tab: tab}}, 0)
+}
+
+// PlaySetlist plays the songs one after another, pausing for gap between
+// them. Stop ends the whole run.
+func (p *Player) PlaySetlist(songs []Song, gap time.Duration) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	
-	if p.isPlaying {
+	if p.isPlaying || len(songs) == 0 {
 		return nil
 	}
 	
-	p.currentTab = tab
-	p.notes = p.convertTabToNotes(tab)
+	p.songs = songs
+	p.gap = gap
+	p.loadSong(0)
 	p.isPlaying = true
-	p.position = 0
-	p.playbackTime = 0
 	
-	// Clear the stop channel
-	select {
-	case <-p.stopChan:
-	default:
-	}
+	// A fresh channel per run, so a loop still winding down from an
+	// earlier Stop cannot pick up this run's signal or touch its state
+	stop := make(chan struct{})
+	p.stopChan = stop
 	
-	go p.playbackLoop()
+	go p.playbackLoop(stop)
 	
 	return nil
 }
@@ -68,14 +82,38 @@ func (p *Player) Stop() {
 	
 	if p.isPlaying {
 		p.isPlaying = false
-		select {
-		case p.stopChan <- true:
-		default:
-		}
+		close(p.stopChan)
+		p.stopChan = nil
 		p.highlighted = nil
 		p.position = 0
 		p.playbackTime = 0
+		p.songs = nil
+		p.song = 0
+	}
+}
+
+// loadSong makes songs[i] the current tab. The caller holds p.mu.
+func (p *Player) loadSong(i int) {
+	song := p.songs[i]
+	tempo := song.Tempo
+	if tempo <= 0 {
+		tempo = song.Tab.Tempo
 	}
+	
+	p.song = i
+	p.currentTab = song.Tab
+	p.tempo = tempo
+	p.notes = TabNotes(song.Tab, tempo, song.Transpose)
+	p.position = 0
+	p.playbackTime = 0
+}
+
+// CurrentSong returns the index of the song playing, or about to play
+// during a gap.
+func (p *Player) CurrentSong() (index int, isPlaying bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.song, p.isPlaying
 }
 
 func (p *Player) IsPlaying() bool {
@@ -98,66 +136,95 @@ func (p *Player) GetPosition() int {
 	return p.position
 }
 
-func (p *Player) convertTabToNotes(tab *models.Tab) []PlayableNote {
-	var notes []PlayableNote
-	
-	// Standard guitar tuning MIDI notes (low to high)
-	// E(6th) A(5th) D(4th) G(3rd) B(2nd) e(1st) - but our array is reversed
-	stringMidiNotes := [6]int{64, 59, 55, 50, 45, 40} // e B G D A E (high to low as displayed)
-	
-	maxLength := 0
-	for _, line := range tab.Content {
-		if len(line) > maxLength {
-			maxLength = len(line)
-		}
-	}
-	
-	// Use the tab's tempo if available, otherwise default
-	tempo := tab.Tempo
+// TabNotes returns the notes a tab plays at tempo, sounding transpose
+// semitones above its written pitch: one sixteenth note per column, in the
+// tab's tuning. Frets are read as models.ParseNotes reads them, so "12"
+// and "<12>" are one note at fret 12. Notes are ordered by column, then
+// string.
+func TabNotes(tab *models.Tab, tempo, transpose int) []PlayableNote {
+	// Use the song's tempo if available, otherwise default
 	if tempo <= 0 {
 		tempo = 120
 	}
-	
+
 	// Calculate note duration based on tempo (assume 16th notes)
 	beatDuration := time.Minute / time.Duration(tempo*4)
-	
-	for pos := 0; pos < maxLength; pos++ {
-		for stringIdx, line := range tab.Content {
-			if pos < len(line) && line[pos] != '-' && line[pos] != '|' && line[pos] != ' ' {
-				if fret, err := strconv.Atoi(string(line[pos])); err == nil && fret >= 0 && fret <= 24 {
-					midiNote := stringMidiNotes[stringIdx] + fret
-					
-					note := PlayableNote{
-						MidiNote: midiNote,
-						Start:    time.Duration(pos) * beatDuration,
-						Duration: beatDuration * 3 / 4, // Note length (slightly shorter than beat)
-						Velocity: 127,
-						String:   stringIdx,
-						Position: pos,
-					}
-					notes = append(notes, note)
-				}
-			}
-		}
+	open := tab.OpenStrings()
+
+	var notes []PlayableNote
+	for _, n := range tab.FrettedNotes() {
+		notes = append(notes, PlayableNote{
+			MidiNote: open[n.String] + n.Fret + transpose,
+			Start:    time.Duration(n.Position) * beatDuration,
+			Duration: beatDuration * 3 / 4, // Note length (slightly shorter than beat)
+			Velocity: 127,
+			String:   n.String,
+			Position: n.Position,
+		})
 	}
-	
+	sort.SliceStable(notes, func(a, b int) bool { return notes[a].Position < notes[b].Position })
+
 	return notes
 }
 
-func (p *Player) playbackLoop() {
+// playbackLoop plays the run that stop belongs to. Once the run has been
+// stopped the player's state belongs to Stop, or to the next run, and the
+// loop leaves it alone.
+func (p *Player) playbackLoop(stop chan struct{}) {
 	defer func() {
 		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.stopChan != stop {
+			return
+		}
+		p.stopChan = nil
 		p.isPlaying = false
 		p.highlighted = nil
 		p.position = 0
 		p.playbackTime = 0
-		p.mu.Unlock()
+		p.songs = nil
+		p.song = 0
 	}()
 	
-	// Use the tab's tempo
-	tempo := 120
-	if p.currentTab != nil && p.currentTab.Tempo > 0 {
-		tempo = p.currentTab.Tempo
+	p.mu.RLock()
+	count, gap := len(p.songs), p.gap
+	p.mu.RUnlock()
+	
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			p.mu.Lock()
+			if p.stopChan != stop {
+				p.mu.Unlock()
+				return
+			}
+			p.loadSong(i)
+			p.highlighted = nil
+			p.mu.Unlock()
+			
+			// Pause between songs
+			select {
+			case <-stop:
+				return
+			case <-time.After(gap):
+			}
+		}
+		
+		if !p.playSong(stop) {
+			return
+		}
+	}
+}
+
+// playSong plays the current tab to its end. It returns false if playback
+// was stopped.
+func (p *Player) playSong(stop chan struct{}) bool {
+	p.mu.RLock()
+	tempo := p.tempo
+	notes := p.notes
+	tab := p.currentTab
+	p.mu.RUnlock()
+	if tempo <= 0 {
+		tempo = 120
 	}
 	
 	beatDuration := time.Minute / time.Duration(tempo*4) // 16th notes
@@ -165,15 +232,15 @@ func (p *Player) playbackLoop() {
 	defer ticker.Stop()
 	
 	maxPos := 0
-	for _, note := range p.notes {
+	for _, note := range notes {
 		if note.Position > maxPos {
 			maxPos = note.Position
 		}
 	}
 	
 	// If no notes, determine max position from tab content
-	if maxPos == 0 && p.currentTab != nil {
-		for _, line := range p.currentTab.Content {
+	if maxPos == 0 && tab != nil {
+		for _, line := range tab.Content {
 			if len(line) > maxPos {
 				maxPos = len(line)
 			}
@@ -184,10 +251,14 @@ func (p *Player) playbackLoop() {
 	
 	for {
 		select {
-		case <-p.stopChan:
-			return
+		case <-stop:
+			return false
 		case <-ticker.C:
 			p.mu.Lock()
+			if p.stopChan != stop {
+				p.mu.Unlock()
+				return false
+			}
 			
 			// Update playback time
 			p.playbackTime = time.Since(startTime)
@@ -220,7 +291,7 @@ func (p *Player) playbackLoop() {
 			// Check if we've reached the end
 			if p.position > maxPos {
 				p.mu.Unlock()
-				return
+				return true
 			}
 			
 			p.mu.Unlock()
@@ -0,0 +1,68 @@
+// internal/midi/player_test.go
+package midi
+
+import (
+	"testing"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+func TestTabNotes(t *testing.T) {
+	type note struct{ pitch, position int }
+	tests := []struct {
+		name      string
+		tuning    [6]string
+		content   [6]string
+		transpose int
+		want      []note
+	}{
+		{
+			name:    "standard",
+			content: [6]string{"0---", "----", "----", "----", "----", "--3-"},
+			want:    []note{{64, 0}, {43, 2}},
+		},
+		{
+			name:   "multi-digit frets in drop D",
+			tuning: [6]string{"e", "B", "G", "D", "A", "D"},
+			content: [6]string{
+				"12----------",
+				"---<12>-----",
+				"------------",
+				"-------10---",
+				"------------",
+				"0---------5-",
+			},
+			want: []note{{76, 0}, {38, 0}, {71, 4}, {60, 7}, {43, 10}},
+		},
+		{
+			name:      "open G, transposed",
+			tuning:    [6]string{"d", "B", "G", "D", "G", "D"},
+			content:   [6]string{"0-", "--", "--", "--", "0-", "-15"},
+			transpose: 2,
+			want:      []note{{64, 0}, {45, 0}, {55, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := models.NewEmptyTab("Test")
+			if tt.tuning != ([6]string{}) {
+				tab.Tuning = tt.tuning
+			}
+			tab.Content = tt.content
+
+			var got []note
+			for _, n := range TabNotes(tab, 120, tt.transpose) {
+				got = append(got, note{n.MidiNote, n.Position})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got notes %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("note %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+// internal/models/journal.go
+package models
+
+import (
+	"time"
+)
+
+// JournalEntry is a snapshot of a tab with unsaved edits, written on every
+// change so the edits survive a crash. TabID is 0 for tabs never saved.
+type JournalEntry struct {
+	ID        int       `json:"id" db:"id"`
+	TabID     int       `json:"tab_id" db:"tab_id"`
+	Tab       Tab       `json:"tab" db:"tab"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,14 @@
+// internal/models/macro.go
+package models
+
+import (
+	"time"
+)
+
+// Macro is a recorded sequence of editor keystrokes stored under a
+// single-letter register, replayed with @<register>.
+type Macro struct {
+	Register  string    `json:"register" db:"register"`
+	Keys      []string  `json:"keys" db:"keys"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,122 @@
+// internal/models/measures.go
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnsPerBeat is the grid resolution of tab content: one column per
+// sixteenth note.
+const ColumnsPerBeat = 4
+
+// Span is a half-open range of tab columns.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Len returns the number of columns in the span.
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// ParseTimeSignature splits a signature like "6/8" into beats and beat unit,
+// falling back to 4/4.
+func ParseTimeSignature(sig string) (beats, unit int) {
+	num, den, ok := strings.Cut(sig, "/")
+	if ok {
+		b, err1 := strconv.Atoi(strings.TrimSpace(num))
+		u, err2 := strconv.Atoi(strings.TrimSpace(den))
+		if err1 == nil && err2 == nil && b > 0 && u > 0 && u <= 16 {
+			return b, u
+		}
+	}
+	return 4, 4
+}
+
+// ColumnsPerMeasure returns how many sixteenth-note columns fill a measure
+// of the tab's time signature.
+func (t *Tab) ColumnsPerMeasure() int {
+	beats, unit := ParseTimeSignature(t.TimeSignature)
+	cols := beats * ColumnsPerBeat * 4 / unit
+	if cols < 1 {
+		cols = 1
+	}
+	return cols
+}
+
+// Length returns the number of columns in the longest string.
+func (t *Tab) Length() int {
+	maxLen := 0
+	for _, line := range t.Content {
+		if n := len([]rune(line)); n > maxLen {
+			maxLen = n
+		}
+	}
+	return maxLen
+}
+
+// Measures splits the tab into measures. Bar lines ('|' on the top string)
+// mark measure boundaries and are included at the end of the measure they
+// close; without bar lines the content is cut every ColumnsPerMeasure columns.
+func (t *Tab) Measures() []Span {
+	length := t.Length()
+	if length == 0 {
+		return nil
+	}
+
+	var measures []Span
+	top := []rune(t.Content[0])
+	start := 0
+	for pos, r := range top {
+		if r == '|' {
+			if pos > start {
+				measures = append(measures, Span{Start: start, End: pos + 1})
+			}
+			start = pos + 1
+		}
+	}
+	if len(measures) > 0 {
+		if start < length {
+			measures = append(measures, Span{Start: start, End: length})
+		}
+		return measures
+	}
+
+	size := t.ColumnsPerMeasure()
+	for start := 0; start < length; start += size {
+		end := start + size
+		if end > length {
+			end = length
+		}
+		measures = append(measures, Span{Start: start, End: end})
+	}
+	return measures
+}
+
+// Systems groups consecutive measures into lines of at most width columns,
+// the way printed tab breaks into stacked systems. A measure wider than
+// width gets a system of its own.
+func (t *Tab) Systems(width int) []Span {
+	var systems []Span
+	for _, m := range t.Measures() {
+		if n := len(systems); n > 0 && m.End-systems[n-1].Start <= width {
+			systems[n-1].End = m.End
+			continue
+		}
+		systems = append(systems, m)
+	}
+	return systems
+}
+
+// Duration returns how long the tab plays at its tempo, one sixteenth note
+// per column.
+func (t *Tab) Duration() time.Duration {
+	tempo := t.Tempo
+	if tempo <= 0 {
+		tempo = 120
+	}
+	return time.Duration(t.Length()) * time.Minute / time.Duration(tempo*ColumnsPerBeat)
+}
@@ -0,0 +1,73 @@
+// internal/models/notes.go
+package models
+
+// MaxFret is the highest fret a run of adjacent digits may spell before it
+// is read as separate single-digit frets ("12" is fret 12, "57" is 5 then 7).
+const MaxFret = 24
+
+// StringLabels names the six tab lines from the highest string to the lowest,
+// matching the order of Tab.Content.
+var StringLabels = [6]string{"e", "B", "G", "D", "A", "E"}
+
+// Note is a fretted note on one string, occupying Width columns starting at
+// Position.
+type Note struct {
+	String   int
+	Position int
+	Width    int
+	Fret     int
+}
+
+// ParseNotes extracts the notes from a single tab line. Adjacent digits are
+// combined into one fret while the result stays within MaxFret.
+func ParseNotes(line string, str int) []Note {
+	var notes []Note
+	runes := []rune(line)
+
+	for pos := 0; pos < len(runes); pos++ {
+		if !isDigit(runes[pos]) {
+			continue
+		}
+
+		fret := int(runes[pos] - '0')
+		width := 1
+		if pos+1 < len(runes) && isDigit(runes[pos+1]) {
+			if combined := fret*10 + int(runes[pos+1]-'0'); fret != 0 && combined <= MaxFret {
+				fret = combined
+				width = 2
+			}
+		}
+
+		notes = append(notes, Note{String: str, Position: pos, Width: width, Fret: fret})
+		pos += width - 1
+	}
+
+	return notes
+}
+
+// FrettedNotes returns every note in the tab ordered by string, then position.
+func (t *Tab) FrettedNotes() []Note {
+	var notes []Note
+	for i, line := range t.Content {
+		notes = append(notes, ParseNotes(line, i)...)
+	}
+	return notes
+}
+
+// ParseStringLabel resolves a string name from StringLabels ("G") or a
+// string number counted from the highest string ("3") to a Content index.
+func ParseStringLabel(label string) (int, bool) {
+	for i, l := range StringLabels {
+		if l == label {
+			return i, true
+		}
+	}
+	if len(label) == 1 && label[0] >= '1' && label[0] <= '6' {
+		return int(label[0] - '1'), true
+	}
+	return 0, false
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
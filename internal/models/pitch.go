@@ -0,0 +1,65 @@
+// internal/models/pitch.go
+package models
+
+import "strings"
+
+// StandardPitches are the MIDI notes of the open strings in standard
+// tuning, highest string first (E4 B3 G3 D3 A2 E2).
+var StandardPitches = [6]int{64, 59, 55, 50, 45, 40}
+
+// pitchNames spells the twelve pitch classes with sharps.
+var pitchNames = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// naturals are the pitch classes of the note letters.
+var naturals = map[string]int{"C": 0, "D": 2, "E": 4, "F": 5, "G": 7, "A": 9, "B": 11}
+
+// ParsePitchClass reads a note name like "E", "f#" or "Bb" as a pitch
+// class from 0 (C) to 11 (B).
+func ParsePitchClass(name string) (int, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, false
+	}
+
+	pc, ok := naturals[strings.ToUpper(name[:1])]
+	if !ok {
+		return 0, false
+	}
+	for _, r := range name[1:] {
+		switch r {
+		case '#':
+			pc++
+		case 'b':
+			pc--
+		default:
+			return 0, false
+		}
+	}
+	return (pc + 12) % 12, true
+}
+
+// PitchName spells a MIDI note as a pitch class and octave, e.g. 40 is
+// ("E", 2).
+func PitchName(midi int) (string, int) {
+	return pitchNames[(midi%12+12)%12], midi/12 - 1
+}
+
+// OpenStrings returns the MIDI note of each open string, highest string
+// first. Tuning names carry no octave, so each string is taken in the
+// octave nearest its note in standard tuning; names that cannot be read
+// keep the standard note.
+func (t *Tab) OpenStrings() [6]int {
+	pitches := StandardPitches
+	for i, name := range t.Tuning {
+		pc, ok := ParsePitchClass(name)
+		if !ok {
+			continue
+		}
+		diff := (pc - pitches[i]%12 + 12) % 12
+		if diff > 6 {
+			diff -= 12
+		}
+		pitches[i] += diff
+	}
+	return pitches
+}
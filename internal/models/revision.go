@@ -0,0 +1,27 @@
+// internal/models/revision.go
+package models
+
+import (
+	"time"
+)
+
+// Revision is a snapshot of a tab as it was saved. Every save that changes
+// the tab adds one.
+type Revision struct {
+	ID        int       `json:"id" db:"id"`
+	TabID     int       `json:"tab_id" db:"tab_id"`
+	Tab       Tab       `json:"tab" db:"tab"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// SameContent reports whether two tabs hold the same music and metadata,
+// ignoring IDs and timestamps.
+func (t *Tab) SameContent(other *Tab) bool {
+	return t.Name == other.Name &&
+		t.Artist == other.Artist &&
+		t.Content == other.Content &&
+		t.Tuning == other.Tuning &&
+		t.Tempo == other.Tempo &&
+		t.TimeSignature == other.TimeSignature &&
+		t.Notes == other.Notes
+}
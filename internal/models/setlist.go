@@ -0,0 +1,52 @@
+// internal/models/setlist.go
+package models
+
+import (
+	"time"
+)
+
+// DefaultSetlistGap is the pause between songs of a new setlist.
+const DefaultSetlistGap = 4 * time.Second
+
+// Setlist is an ordered list of tabs to play at a gig, with a pause of Gap
+// between songs.
+type Setlist struct {
+	ID        int            `json:"id" db:"id"`
+	Name      string         `json:"name" db:"name"`
+	Gap       time.Duration  `json:"gap" db:"gap_ms"`
+	Entries   []SetlistEntry `json:"entries" db:"-"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// SetlistEntry is one song of a setlist and how to play it there.
+type SetlistEntry struct {
+	TabID     int    `json:"tab_id" db:"tab_id"`
+	Notes     string `json:"notes" db:"notes"`
+	Capo      int    `json:"capo" db:"capo"`           // fret; the tab is written relative to it
+	Transpose int    `json:"transpose" db:"transpose"` // semitones
+	Tempo     int    `json:"tempo" db:"tempo"`         // 0 keeps the tab's tempo
+}
+
+func NewSetlist(name string) *Setlist {
+	return &Setlist{
+		Name:      name,
+		Gap:       DefaultSetlistGap,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// PitchOffset is how many semitones the entry sounds above the tab as
+// written: the capo plus any transposition.
+func (e SetlistEntry) PitchOffset() int {
+	return e.Capo + e.Transpose
+}
+
+// TempoFor returns the tempo to play tab at in this entry.
+func (e SetlistEntry) TempoFor(tab *Tab) int {
+	if e.Tempo > 0 {
+		return e.Tempo
+	}
+	return tab.Tempo
+}
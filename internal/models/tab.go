@@ -2,19 +2,24 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
 type Tab struct {
-	ID            int       `json:"id" db:"id"`
-	Name          string    `json:"name" db:"name"`
-	Artist        string    `json:"artist" db:"artist"`
-	Content       [6]string `json:"content" db:"content"` // 6 strings
-	Tuning        [6]string `json:"tuning" db:"tuning"`   // E A D G B e
-	Tempo         int       `json:"tempo" db:"tempo"`
-	TimeSignature string    `json:"time_signature" db:"time_signature"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID            int        `json:"id" db:"id"`
+	Name          string     `json:"name" db:"name"`
+	Artist        string     `json:"artist" db:"artist"`
+	Content       [6]string  `json:"content" db:"content"` // 6 strings
+	Tuning        [6]string  `json:"tuning" db:"tuning"`   // E A D G B e
+	Tempo         int        `json:"tempo" db:"tempo"`
+	TimeSignature string     `json:"time_signature" db:"time_signature"`
+	Notes         string     `json:"notes" db:"notes"` // free text: lyrics, performance notes
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // set while the tab is in the trash
+	Favorite      bool       `json:"favorite" db:"favorite"`
+	Tags          []string   `json:"tags,omitempty" db:"-"` // sorted; see Storage.AddTag
 }
 
 func NewEmptyTab(name string) *Tab {
@@ -44,6 +49,8 @@ const (
 	ViewBrowser
 	ViewSettings
 	ViewHelp
+	ViewHistory
+	ViewSetlist
 )
 
 type EditMode int
@@ -67,4 +74,55 @@ type SessionState struct {
 	ViewMode      ViewMode
 	PlaybackState PlaybackState
 	EditMode      EditMode
+	Modified      bool // CurrentTab has edits that are not in storage
+}
+
+// TuningLabels returns the names of the strings, highest first, padded to
+// a common width, falling back to standard tuning for unnamed strings.
+func (t *Tab) TuningLabels() []string {
+	labels := make([]string, len(t.Tuning))
+	width := 0
+	for i, name := range t.Tuning {
+		if name == "" {
+			name = StringLabels[i]
+		}
+		labels[i] = name
+		if n := len([]rune(name)); n > width {
+			width = n
+		}
+	}
+	for i := range labels {
+		labels[i] += strings.Repeat(" ", width-len([]rune(labels[i])))
+	}
+	return labels
+}
+
+// TuningString lists the tuning from the lowest string to the highest, the
+// way tunings are usually written ("E A D G B e").
+func (t *Tab) TuningString() string {
+	var b strings.Builder
+	for i := len(t.Tuning) - 1; i >= 0; i-- {
+		b.WriteString(t.Tuning[i])
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// ParseTuning reads a tuning written the way TuningString writes it, six
+// string names from the lowest to the highest ("D A D G B e").
+func ParseTuning(value string) ([6]string, bool) {
+	var tuning [6]string
+	fields := strings.Fields(value)
+	if len(fields) != len(tuning) {
+		return tuning, false
+	}
+	for i, f := range fields {
+		if _, ok := ParsePitchClass(f); !ok {
+			return tuning, false
+		}
+		tuning[len(fields)-1-i] = f
+	}
+	return tuning, true
 }
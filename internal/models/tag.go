@@ -0,0 +1,9 @@
+// internal/models/tag.go
+package models
+
+// Tag is a label shared by any number of tabs, such as "setlist" or
+// "learning". Count is the number of tabs outside the trash carrying it.
+type Tag struct {
+	Name  string `json:"name" db:"name"`
+	Count int    `json:"count" db:"count"`
+}
@@ -0,0 +1,168 @@
+// internal/storage/fts.go
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// migrateFTS creates the full-text index over tabs and backfills rows saved
+// before it existed. Builds of go-sqlite3 without the sqlite_fts5 tag lack
+// the module; search then falls back to LIKE.
+func (s *SQLiteStorage) migrateFTS() error {
+	_, err := s.db.Exec(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS tabs_fts USING fts5(
+			name, artist, notes, frets, lines,
+			tokenize = 'unicode61'
+		)
+	`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			s.fts = false
+			return nil
+		}
+		return err
+	}
+	s.fts = true
+
+	// Drop entries for tabs deleted by a build without FTS5
+	if _, err := s.db.Exec(`DELETE FROM tabs_fts WHERE rowid NOT IN (SELECT id FROM tabs)`); err != nil {
+		return err
+	}
+
+	rows, err := s.db.Query(`SELECT ` + tabColumns + ` FROM tabs WHERE id NOT IN (SELECT rowid FROM tabs_fts)`)
+	if err != nil {
+		return err
+	}
+	missing := scanTabs(rows)
+	rows.Close()
+
+	for i := range missing {
+		if err := s.indexTab(&missing[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexTab replaces the full-text entry of tab.
+func (s *SQLiteStorage) indexTab(tab *models.Tab) error {
+	if !s.fts {
+		return nil
+	}
+	if err := s.unindexTab(tab.ID); err != nil {
+		return err
+	}
+
+	frets, lines := normalizeFrets(tab)
+	_, err := s.db.Exec(`INSERT INTO tabs_fts (rowid, name, artist, notes, frets, lines) VALUES (?, ?, ?, ?, ?, ?)`,
+		tab.ID, tab.Name, tab.Artist, tab.Notes, frets, lines)
+	return err
+}
+
+func (s *SQLiteStorage) unindexTab(id int) error {
+	if !s.fts {
+		return nil
+	}
+	_, err := s.db.Exec(`DELETE FROM tabs_fts WHERE rowid = ?`, id)
+	return err
+}
+
+func (s *SQLiteStorage) searchFTS(match string) ([]models.Tab, error) {
+	// Column weights: name, artist, notes, frets, lines
+	query := `
+		SELECT ` + tabColumns + ` FROM tabs
+		JOIN (
+			SELECT rowid AS fts_id, bm25(tabs_fts, 10.0, 5.0, 2.0, 1.0, 1.0) AS rank
+			FROM tabs_fts WHERE tabs_fts MATCH ?
+		) ON tabs.id = fts_id
+		WHERE tabs.deleted_at IS NULL
+		ORDER BY rank
+	`
+	rows, err := s.db.Query(query, match)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.withTags(scanTabs(rows))
+}
+
+// normalizeFrets renders a tab's notes as search tokens. frets lists every
+// note in playing order as f<fret>; lines lists each string's notes in turn
+// as s<string>f<fret>, strings numbered from the highest as 1.
+func normalizeFrets(tab *models.Tab) (frets, lines string) {
+	notes := tab.FrettedNotes()
+
+	var l strings.Builder
+	for _, n := range notes {
+		fmt.Fprintf(&l, "s%df%d ", n.String+1, n.Fret)
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].Position < notes[j].Position
+	})
+	var f strings.Builder
+	for _, n := range notes {
+		fmt.Fprintf(&f, "f%d ", n.Fret)
+	}
+
+	return strings.TrimSpace(f.String()), strings.TrimSpace(l.String())
+}
+
+var riffPattern = regexp.MustCompile(`^(?:([eBGDAE1-6]):)?(\d+(?:-\d+)*)$`)
+
+// ftsQuery translates a search box query into an FTS5 expression. Words
+// match as prefixes anywhere in name, artist or notes; riffs such as 7-9-7
+// match consecutive notes, and G:7-9-7 (or 3:7-9-7) consecutive notes on
+// one string. All terms must match.
+func ftsQuery(query string) string {
+	var parts []string
+	for _, term := range strings.Fields(query) {
+		if m := riffPattern.FindStringSubmatch(term); m != nil && (m[1] != "" || strings.Contains(m[2], "-")) {
+			if phrase := riffPhrase(m[1], strings.Split(m[2], "-")); phrase != "" {
+				parts = append(parts, phrase)
+				continue
+			}
+		}
+
+		// Only letters and digits make tokens; a term of punctuation alone
+		// would be an empty phrase, which FTS5 rejects
+		words := strings.FieldsFunc(term, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) > 0 {
+			parts = append(parts, `{name artist notes} : "`+strings.Join(words, " ")+`"*`)
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func riffPhrase(label string, frets []string) string {
+	prefix := ""
+	column := "frets"
+	if label != "" {
+		str, ok := models.ParseStringLabel(label)
+		if !ok {
+			return ""
+		}
+		prefix = "s" + strconv.Itoa(str+1)
+		column = "lines"
+	}
+
+	tokens := make([]string, 0, len(frets))
+	for _, fret := range frets {
+		n, err := strconv.Atoi(fret)
+		if err != nil || n > models.MaxFret {
+			return ""
+		}
+		tokens = append(tokens, prefix+"f"+strconv.Itoa(n))
+	}
+	return column + ` : "` + strings.Join(tokens, " ") + `"`
+}
@@ -0,0 +1,220 @@
+// internal/storage/migrations.go
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// migration upgrades the schema by one version. Migrations are never edited
+// once released; a schema change is a new migration at the end of the list.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// migrations brings a database from version 0 to schemaVersion. Databases
+// created before versioning report version 0 but may already hold some of
+// these tables and columns, so the early steps must tolerate them.
+var migrations = []migration{
+	{1, "create tabs", execSQL(`
+		CREATE TABLE IF NOT EXISTS tabs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			artist TEXT DEFAULT '',
+			content TEXT NOT NULL,
+			tuning TEXT NOT NULL,
+			tempo INTEGER DEFAULT 120,
+			time_signature TEXT DEFAULT '4/4',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_tabs_name ON tabs(name);
+		CREATE INDEX IF NOT EXISTS idx_tabs_updated_at ON tabs(updated_at DESC);
+	`)},
+	{2, "create macros", execSQL(`
+		CREATE TABLE IF NOT EXISTS macros (
+			register TEXT PRIMARY KEY,
+			keys TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+	`)},
+	{3, "create journal", execSQL(`
+		CREATE TABLE IF NOT EXISTS journal (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			tab_id INTEGER NOT NULL DEFAULT 0,
+			tab TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+	`)},
+	{4, "create settings", execSQL(`
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+	`)},
+	{5, "add tab notes", addColumn("tabs", "notes", "TEXT NOT NULL DEFAULT ''")},
+	{6, "add trash", addColumn("tabs", "deleted_at", "DATETIME")},
+	{7, "create revisions", execSQL(`
+		CREATE TABLE IF NOT EXISTS revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			tab_id INTEGER NOT NULL,
+			tab TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_revisions_tab_id ON revisions(tab_id, created_at DESC);
+	`)},
+	{8, "create tags", execSQL(`
+		CREATE TABLE tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE
+		);
+
+		CREATE TABLE tab_tags (
+			tab_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (tab_id, tag_id)
+		);
+
+		CREATE INDEX idx_tab_tags_tag_id ON tab_tags(tag_id);
+	`)},
+	{9, "add favorites", execSQL(`ALTER TABLE tabs ADD COLUMN favorite INTEGER NOT NULL DEFAULT 0`)},
+	{10, "create setlists", execSQL(`
+		CREATE TABLE setlists (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			gap_ms INTEGER NOT NULL DEFAULT 4000,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE setlist_entries (
+			setlist_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			tab_id INTEGER NOT NULL,
+			notes TEXT NOT NULL DEFAULT '',
+			capo INTEGER NOT NULL DEFAULT 0,
+			transpose INTEGER NOT NULL DEFAULT 0,
+			tempo INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (setlist_id, position)
+		);
+	`)},
+}
+
+// schemaVersion is the newest schema this build understands.
+var schemaVersion = migrations[len(migrations)-1].version
+
+func execSQL(query string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// addColumn adds a column unless it is already there, as it is in
+// databases that predate versioning.
+func addColumn(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		rows, err := tx.Query(`PRAGMA table_info(` + table + `)`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var cid, notNull, pk int
+			var name, colType string
+			var dflt sql.NullString
+			if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+				return err
+			}
+			if name == column {
+				return nil
+			}
+		}
+		rows.Close()
+
+		_, err = tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + definition)
+		return err
+	}
+}
+
+// migrate upgrades the database to schemaVersion, one transaction per
+// migration, after backing up a database that already holds data. It
+// refuses databases written by a newer build. The full-text index depends
+// on how SQLite was built rather than on the schema version, so it is
+// checked on every open instead.
+func (s *SQLiteStorage) migrate() error {
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+
+	if version > schemaVersion {
+		return fmt.Errorf("%s has schema version %d, but this build only supports up to %d; please upgrade tuitar",
+			s.path, version, schemaVersion)
+	}
+
+	if version < schemaVersion {
+		if err := s.backup(version); err != nil {
+			return fmt.Errorf("backing up before upgrade: %w", err)
+		}
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		if err := s.runMigration(m); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+	}
+
+	return s.migrateFTS()
+}
+
+func (s *SQLiteStorage) runMigration(m migration) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+	// PRAGMA takes no parameters; the version is an int from our own table
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, m.version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// backup copies the database next to itself as <path>.v<version>.bak before
+// an upgrade. New, empty and in-memory databases have nothing to lose. An
+// existing backup of the same version is kept as is.
+func (s *SQLiteStorage) backup(version int) error {
+	if s.path == "" || s.path == ":memory:" || strings.HasPrefix(s.path, "file:") {
+		return nil
+	}
+
+	var tables int
+	if err := s.db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables); err != nil {
+		return err
+	}
+	if tables == 0 {
+		return nil
+	}
+
+	dest := fmt.Sprintf("%s.v%d.bak", s.path, version)
+	if _, err := os.Stat(dest); err == nil {
+		return nil
+	}
+	_, err := s.db.Exec(`VACUUM INTO ?`, dest)
+	return err
+}
@@ -0,0 +1,59 @@
+// internal/storage/revisions.go
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// addRevision snapshots a just-saved tab, unless it matches the latest
+// revision already (a save without edits, or an autosave after Ctrl+S).
+func (s *SQLiteStorage) addRevision(tab *models.Tab) error {
+	var latestJSON string
+	err := s.db.QueryRow(`SELECT tab FROM revisions WHERE tab_id = ? ORDER BY id DESC LIMIT 1`, tab.ID).
+		Scan(&latestJSON)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		var latest models.Tab
+		if json.Unmarshal([]byte(latestJSON), &latest) == nil && latest.SameContent(tab) {
+			return nil
+		}
+	}
+
+	tabJSON, err := json.Marshal(tab)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO revisions (tab_id, tab, created_at) VALUES (?, ?, ?)`,
+		tab.ID, tabJSON, tab.UpdatedAt)
+	return err
+}
+
+func (s *SQLiteStorage) LoadRevisions(tabID int) ([]models.Revision, error) {
+	rows, err := s.db.Query(`SELECT id, tab_id, tab, created_at FROM revisions WHERE tab_id = ? ORDER BY id DESC`, tabID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []models.Revision
+	for rows.Next() {
+		var rev models.Revision
+		var tabJSON string
+
+		if err := rows.Scan(&rev.ID, &rev.TabID, &tabJSON, &rev.CreatedAt); err != nil {
+			continue
+		}
+		if err := json.Unmarshal([]byte(tabJSON), &rev.Tab); err != nil {
+			continue
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, nil
+}
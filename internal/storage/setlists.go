@@ -0,0 +1,106 @@
+// internal/storage/setlists.go
+package storage
+
+import (
+	"time"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+func (s *SQLiteStorage) SaveSetlist(setlist *models.Setlist) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	gap := setlist.Gap.Milliseconds()
+	if setlist.ID == 0 {
+		query := `INSERT INTO setlists (name, gap_ms, created_at, updated_at) VALUES (?, ?, ?, ?)`
+		result, err := tx.Exec(query, setlist.Name, gap, setlist.CreatedAt, now)
+		if err != nil {
+			return err
+		}
+
+		id, _ := result.LastInsertId()
+		setlist.ID = int(id)
+	} else {
+		query := `UPDATE setlists SET name=?, gap_ms=?, updated_at=? WHERE id=?`
+		if _, err := tx.Exec(query, setlist.Name, gap, now, setlist.ID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM setlist_entries WHERE setlist_id = ?`, setlist.ID); err != nil {
+		return err
+	}
+	for i, entry := range setlist.Entries {
+		_, err := tx.Exec(`
+			INSERT INTO setlist_entries (setlist_id, position, tab_id, notes, capo, transpose, tempo)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, setlist.ID, i, entry.TabID, entry.Notes, entry.Capo, entry.Transpose, entry.Tempo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	setlist.UpdatedAt = now
+	return nil
+}
+
+// LoadSetlists returns every setlist with its entries, by name.
+func (s *SQLiteStorage) LoadSetlists() ([]models.Setlist, error) {
+	rows, err := s.db.Query(`SELECT id, name, gap_ms, created_at, updated_at FROM setlists ORDER BY name COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var setlists []models.Setlist
+	index := make(map[int]int)
+	for rows.Next() {
+		var setlist models.Setlist
+		var gap int64
+		if err := rows.Scan(&setlist.ID, &setlist.Name, &gap, &setlist.CreatedAt, &setlist.UpdatedAt); err != nil {
+			continue
+		}
+		setlist.Gap = time.Duration(gap) * time.Millisecond
+		index[setlist.ID] = len(setlists)
+		setlists = append(setlists, setlist)
+	}
+	rows.Close()
+
+	entries, err := s.db.Query(`
+		SELECT setlist_id, tab_id, notes, capo, transpose, tempo
+		FROM setlist_entries ORDER BY setlist_id, position
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer entries.Close()
+
+	for entries.Next() {
+		var setlistID int
+		var entry models.SetlistEntry
+		if err := entries.Scan(&setlistID, &entry.TabID, &entry.Notes, &entry.Capo, &entry.Transpose, &entry.Tempo); err != nil {
+			continue
+		}
+		if i, ok := index[setlistID]; ok {
+			setlists[i].Entries = append(setlists[i].Entries, entry)
+		}
+	}
+
+	return setlists, nil
+}
+
+func (s *SQLiteStorage) DeleteSetlist(id int) error {
+	_, err := s.db.Exec(`
+		DELETE FROM setlist_entries WHERE setlist_id = ?;
+		DELETE FROM setlists WHERE id = ?;
+	`, id, id)
+	return err
+}
@@ -11,7 +11,9 @@ import (
 )
 
 type SQLiteStorage struct {
-	db *sql.DB
+	db   *sql.DB
+	path string
+	fts  bool // SQLite was built with FTS5 (-tags sqlite_fts5)
 }
 
 func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
@@ -20,34 +22,52 @@ func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 		return nil, err
 	}
 	
-	storage := &SQLiteStorage{db: db}
+	storage := &SQLiteStorage{db: db, path: dbPath}
 	if err := storage.migrate(); err != nil {
+		db.Close()
 		return nil, err
 	}
 	
 	return storage, nil
 }
 
-func (s *SQLiteStorage) migrate() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS tabs (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		name TEXT NOT NULL,
-		artist TEXT DEFAULT '',
-		content TEXT NOT NULL,
-		tuning TEXT NOT NULL,
-		tempo INTEGER DEFAULT 120,
-		time_signature TEXT DEFAULT '4/4',
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	
-	CREATE INDEX IF NOT EXISTS idx_tabs_name ON tabs(name);
-	CREATE INDEX IF NOT EXISTS idx_tabs_updated_at ON tabs(updated_at DESC);
-	`
-	
-	_, err := s.db.Exec(query)
-	return err
+// tabColumns lists the tabs columns in the order scanTab reads them.
+const tabColumns = `id, name, artist, content, tuning, tempo, time_signature, notes, created_at, updated_at, deleted_at, favorite`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTab(row rowScanner) (*models.Tab, error) {
+	var tab models.Tab
+	var contentJSON, tuningJSON string
+	var deletedAt sql.NullTime
+
+	err := row.Scan(&tab.ID, &tab.Name, &tab.Artist, &contentJSON, &tuningJSON,
+		&tab.Tempo, &tab.TimeSignature, &tab.Notes, &tab.CreatedAt, &tab.UpdatedAt, &deletedAt, &tab.Favorite)
+	if err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		tab.DeletedAt = &deletedAt.Time
+	}
+
+	json.Unmarshal([]byte(contentJSON), &tab.Content)
+	json.Unmarshal([]byte(tuningJSON), &tab.Tuning)
+
+	return &tab, nil
+}
+
+func scanTabs(rows *sql.Rows) []models.Tab {
+	var tabs []models.Tab
+	for rows.Next() {
+		tab, err := scanTab(rows)
+		if err != nil {
+			continue
+		}
+		tabs = append(tabs, *tab)
+	}
+	return tabs
 }
 
 func (s *SQLiteStorage) SaveTab(tab *models.Tab) error {
@@ -57,11 +77,11 @@ func (s *SQLiteStorage) SaveTab(tab *models.Tab) error {
 	if tab.ID == 0 {
 		// Insert new tab
 		query := `
-			INSERT INTO tabs (name, artist, content, tuning, tempo, time_signature, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO tabs (name, artist, content, tuning, tempo, time_signature, notes, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`
 		result, err := s.db.Exec(query, tab.Name, tab.Artist, contentJSON, tuningJSON,
-			tab.Tempo, tab.TimeSignature, tab.CreatedAt, time.Now())
+			tab.Tempo, tab.TimeSignature, tab.Notes, tab.CreatedAt, time.Now())
 		if err != nil {
 			return err
 		}
@@ -72,104 +92,265 @@ func (s *SQLiteStorage) SaveTab(tab *models.Tab) error {
 		// Update existing tab
 		query := `
 			UPDATE tabs SET name=?, artist=?, content=?, tuning=?, tempo=?, 
-			time_signature=?, updated_at=? WHERE id=?
+			time_signature=?, notes=?, updated_at=? WHERE id=?
 		`
 		_, err := s.db.Exec(query, tab.Name, tab.Artist, contentJSON, tuningJSON,
-			tab.Tempo, tab.TimeSignature, time.Now(), tab.ID)
+			tab.Tempo, tab.TimeSignature, tab.Notes, time.Now(), tab.ID)
 		if err != nil {
 			return err
 		}
 	}
 	
 	tab.UpdatedAt = time.Now()
-	return nil
+	if err := s.addRevision(tab); err != nil {
+		return err
+	}
+	return s.indexTab(tab)
 }
 
 func (s *SQLiteStorage) LoadTab(id int) (*models.Tab, error) {
-	query := `SELECT * FROM tabs WHERE id = ?`
-	row := s.db.QueryRow(query, id)
-	
-	var tab models.Tab
-	var contentJSON, tuningJSON string
-	
-	err := row.Scan(&tab.ID, &tab.Name, &tab.Artist, &contentJSON, &tuningJSON,
-		&tab.Tempo, &tab.TimeSignature, &tab.CreatedAt, &tab.UpdatedAt)
+	query := `SELECT ` + tabColumns + ` FROM tabs WHERE id = ?`
+	tab, err := scanTab(s.db.QueryRow(query, id))
 	if err != nil {
 		return nil, err
 	}
-	
-	json.Unmarshal([]byte(contentJSON), &tab.Content)
-	json.Unmarshal([]byte(tuningJSON), &tab.Tuning)
-	
-	return &tab, nil
+
+	tabs, err := s.withTags([]models.Tab{*tab})
+	if err != nil {
+		return nil, err
+	}
+	return &tabs[0], nil
 }
 
 func (s *SQLiteStorage) LoadAllTabs() ([]models.Tab, error) {
-	query := `SELECT * FROM tabs ORDER BY updated_at DESC`
+	query := `SELECT ` + tabColumns + ` FROM tabs WHERE deleted_at IS NULL ORDER BY updated_at DESC`
 	rows, err := s.db.Query(query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	
-	var tabs []models.Tab
-	for rows.Next() {
-		var tab models.Tab
-		var contentJSON, tuningJSON string
-		
-		err := rows.Scan(&tab.ID, &tab.Name, &tab.Artist, &contentJSON, &tuningJSON,
-			&tab.Tempo, &tab.TimeSignature, &tab.CreatedAt, &tab.UpdatedAt)
-		if err != nil {
-			continue
-		}
-		
-		json.Unmarshal([]byte(contentJSON), &tab.Content)
-		json.Unmarshal([]byte(tuningJSON), &tab.Tuning)
-		
-		tabs = append(tabs, tab)
-	}
-	
-	return tabs, nil
+	return s.withTags(scanTabs(rows))
 }
 
+// DeleteTab moves a tab to the trash. It stays indexed so that a restored
+// tab is searchable again straight away.
 func (s *SQLiteStorage) DeleteTab(id int) error {
-	query := `DELETE FROM tabs WHERE id = ?`
-	_, err := s.db.Exec(query, id)
+	query := `UPDATE tabs SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	_, err := s.db.Exec(query, time.Now(), id)
+	return err
+}
+
+func (s *SQLiteStorage) LoadTrash() ([]models.Tab, error) {
+	query := `SELECT ` + tabColumns + ` FROM tabs WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.withTags(scanTabs(rows))
+}
+
+func (s *SQLiteStorage) RestoreTab(id int) error {
+	_, err := s.db.Exec(`UPDATE tabs SET deleted_at = NULL WHERE id = ?`, id)
 	return err
 }
 
+// PurgeTab deletes a trashed tab for good. Tabs that are not in the trash
+// are left alone.
+func (s *SQLiteStorage) PurgeTab(id int) error {
+	query := `DELETE FROM tabs WHERE id = ? AND deleted_at IS NOT NULL`
+	if _, err := s.db.Exec(query, id); err != nil {
+		return err
+	}
+	if err := s.dropOrphans(); err != nil {
+		return err
+	}
+	return s.unindexTab(id)
+}
+
+// dropOrphans removes the history, tags and setlist entries of purged
+// tabs, and tags that no tab uses any more.
+func (s *SQLiteStorage) dropOrphans() error {
+	_, err := s.db.Exec(`
+		DELETE FROM revisions WHERE tab_id NOT IN (SELECT id FROM tabs);
+		DELETE FROM setlist_entries WHERE tab_id NOT IN (SELECT id FROM tabs);
+		DELETE FROM tab_tags WHERE tab_id NOT IN (SELECT id FROM tabs);
+		DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM tab_tags);
+	`)
+	return err
+}
+
+// PurgeTrash deletes every tab trashed before the given time and returns
+// how many were removed.
+func (s *SQLiteStorage) PurgeTrash(before time.Time) (int, error) {
+	result, err := s.db.Exec(`DELETE FROM tabs WHERE deleted_at IS NOT NULL AND deleted_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	purged, _ := result.RowsAffected()
+	if purged == 0 {
+		return 0, nil
+	}
+
+	if err := s.dropOrphans(); err != nil {
+		return int(purged), err
+	}
+	if s.fts {
+		if _, err := s.db.Exec(`DELETE FROM tabs_fts WHERE rowid NOT IN (SELECT id FROM tabs)`); err != nil {
+			return int(purged), err
+		}
+	}
+	return int(purged), nil
+}
+
+func (s *SQLiteStorage) FullTextSearch() bool {
+	return s.fts
+}
+
+// SearchTabs returns tabs matching query, best matches first. With FTS5 the
+// query is matched against names, artists, notes and riffs (see ftsQuery);
+// without it, name, artist, tuning and notes are matched as substrings.
 func (s *SQLiteStorage) SearchTabs(query string) ([]models.Tab, error) {
+	if s.fts {
+		if match := ftsQuery(query); match != "" {
+			if tabs, err := s.searchFTS(match); err == nil {
+				return tabs, nil
+			}
+			// A query FTS5 cannot parse is still worth a substring match
+		}
+	}
+
 	sqlQuery := `
-		SELECT * FROM tabs 
-		WHERE name LIKE ? OR artist LIKE ? 
+		SELECT ` + tabColumns + ` FROM tabs 
+		WHERE deleted_at IS NULL
+		AND (name LIKE ? OR artist LIKE ? OR tuning LIKE ? OR notes LIKE ?)
 		ORDER BY updated_at DESC
 	`
 	
 	searchTerm := "%" + query + "%"
-	rows, err := s.db.Query(sqlQuery, searchTerm, searchTerm)
+	rows, err := s.db.Query(sqlQuery, searchTerm, searchTerm, searchTerm, searchTerm)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	
-	var tabs []models.Tab
+	return s.withTags(scanTabs(rows))
+}
+
+func (s *SQLiteStorage) SaveMacro(macro *models.Macro) error {
+	keysJSON, _ := json.Marshal(macro.Keys)
+
+	query := `
+		INSERT INTO macros (register, keys, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(register) DO UPDATE SET keys=excluded.keys, updated_at=excluded.updated_at
+	`
+	_, err := s.db.Exec(query, macro.Register, keysJSON, time.Now())
+	if err != nil {
+		return err
+	}
+
+	macro.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *SQLiteStorage) LoadMacros() ([]models.Macro, error) {
+	rows, err := s.db.Query(`SELECT register, keys, updated_at FROM macros ORDER BY register`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var macros []models.Macro
 	for rows.Next() {
-		var tab models.Tab
-		var contentJSON, tuningJSON string
-		
-		err := rows.Scan(&tab.ID, &tab.Name, &tab.Artist, &contentJSON, &tuningJSON,
-			&tab.Tempo, &tab.TimeSignature, &tab.CreatedAt, &tab.UpdatedAt)
+		var macro models.Macro
+		var keysJSON string
+
+		if err := rows.Scan(&macro.Register, &keysJSON, &macro.UpdatedAt); err != nil {
+			continue
+		}
+
+		json.Unmarshal([]byte(keysJSON), &macro.Keys)
+		macros = append(macros, macro)
+	}
+
+	return macros, nil
+}
+
+func (s *SQLiteStorage) WriteJournal(entry *models.JournalEntry) error {
+	tabJSON, err := json.Marshal(entry.Tab)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if entry.ID == 0 {
+		query := `INSERT INTO journal (tab_id, tab, updated_at) VALUES (?, ?, ?)`
+		result, err := s.db.Exec(query, entry.TabID, tabJSON, now)
 		if err != nil {
+			return err
+		}
+
+		id, _ := result.LastInsertId()
+		entry.ID = int(id)
+	} else {
+		query := `UPDATE journal SET tab_id=?, tab=?, updated_at=? WHERE id=?`
+		if _, err := s.db.Exec(query, entry.TabID, tabJSON, now, entry.ID); err != nil {
+			return err
+		}
+	}
+
+	entry.UpdatedAt = now
+	return nil
+}
+
+func (s *SQLiteStorage) LoadJournal() ([]models.JournalEntry, error) {
+	rows, err := s.db.Query(`SELECT id, tab_id, tab, updated_at FROM journal ORDER BY updated_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.JournalEntry
+	for rows.Next() {
+		var entry models.JournalEntry
+		var tabJSON string
+
+		if err := rows.Scan(&entry.ID, &entry.TabID, &tabJSON, &entry.UpdatedAt); err != nil {
 			continue
 		}
-		
-		json.Unmarshal([]byte(contentJSON), &tab.Content)
-		json.Unmarshal([]byte(tuningJSON), &tab.Tuning)
-		
-		tabs = append(tabs, tab)
+		if err := json.Unmarshal([]byte(tabJSON), &entry.Tab); err != nil {
+			continue
+		}
+
+		entries = append(entries, entry)
 	}
-	
-	return tabs, nil
+
+	return entries, nil
+}
+
+func (s *SQLiteStorage) ClearJournal(id int) error {
+	_, err := s.db.Exec(`DELETE FROM journal WHERE id = ?`, id)
+	return err
+}
+
+func (s *SQLiteStorage) GetSetting(key string) (string, error) {
+	var value string
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+func (s *SQLiteStorage) SetSetting(key, value string) error {
+	query := `
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value=excluded.value
+	`
+	_, err := s.db.Exec(query, key, value)
+	return err
 }
 
 func (s *SQLiteStorage) Close() error {
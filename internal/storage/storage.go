@@ -1,12 +1,54 @@
 // internal/storage/storage.go
 package storage
 
-import "github.com/Cod-e-Codes/tuitar/internal/models"
+import (
+	"time"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
 
 type Storage interface {
 	SaveTab(tab *models.Tab) error
 	LoadTab(id int) (*models.Tab, error)
 	LoadAllTabs() ([]models.Tab, error)
-	DeleteTab(id int) error
+	DeleteTab(id int) error // moves the tab to the trash
 	SearchTabs(query string) ([]models.Tab, error)
+	// FullTextSearch reports whether SearchTabs ranks notes and riffs with
+	// FTS5, rather than matching substrings.
+	FullTextSearch() bool
+
+	// Trashed tabs are left out of LoadAllTabs and SearchTabs until they
+	// are restored or purged for good.
+	LoadTrash() ([]models.Tab, error)
+	RestoreTab(id int) error
+	PurgeTab(id int) error
+	PurgeTrash(before time.Time) (int, error)
+
+	// SaveTab records a revision whenever the saved tab differs from its
+	// latest one. Revisions are listed newest first.
+	LoadRevisions(tabID int) ([]models.Revision, error)
+
+	// Tags and the favorite flag are kept apart from the tab's content:
+	// SaveTab neither writes them nor records a revision for them.
+	AddTag(tabID int, tag string) error
+	RemoveTag(tabID int, tag string) error
+	ListTags() ([]models.Tag, error)
+	TabsByTag(tag string) ([]models.Tab, error)
+	SetFavorite(tabID int, favorite bool) error
+
+	// SaveSetlist writes a setlist together with its entries, in order.
+	SaveSetlist(setlist *models.Setlist) error
+	LoadSetlists() ([]models.Setlist, error)
+	DeleteSetlist(id int) error
+
+	SaveMacro(macro *models.Macro) error
+	LoadMacros() ([]models.Macro, error)
+
+	WriteJournal(entry *models.JournalEntry) error
+	LoadJournal() ([]models.JournalEntry, error)
+	ClearJournal(id int) error
+
+	// Settings are small named preferences; missing keys read as "".
+	GetSetting(key string) (string, error)
+	SetSetting(key, value string) error
 }
@@ -0,0 +1,122 @@
+// internal/storage/tags.go
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// AddTag tags a tab, creating the tag on first use. Tag names are matched
+// without regard to case and keep the spelling they were created with.
+func (s *SQLiteStorage) AddTag(tabID int, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return fmt.Errorf("empty tag")
+	}
+
+	if _, err := s.db.Exec(`INSERT INTO tags (name) VALUES (?) ON CONFLICT(name) DO NOTHING`, tag); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO tab_tags (tab_id, tag_id)
+		SELECT ?, id FROM tags WHERE name = ?
+		ON CONFLICT DO NOTHING
+	`, tabID, tag)
+	return err
+}
+
+// RemoveTag untags a tab and drops the tag once no tab carries it.
+func (s *SQLiteStorage) RemoveTag(tabID int, tag string) error {
+	_, err := s.db.Exec(`
+		DELETE FROM tab_tags
+		WHERE tab_id = ? AND tag_id = (SELECT id FROM tags WHERE name = ?)
+	`, tabID, strings.TrimSpace(tag))
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM tab_tags)`)
+	return err
+}
+
+// ListTags returns every tag by name, with how many tabs outside the trash
+// carry it.
+func (s *SQLiteStorage) ListTags() ([]models.Tag, error) {
+	rows, err := s.db.Query(`
+		SELECT tags.name, COUNT(tabs.id) FROM tags
+		LEFT JOIN tab_tags ON tab_tags.tag_id = tags.id
+		LEFT JOIN tabs ON tabs.id = tab_tags.tab_id AND tabs.deleted_at IS NULL
+		GROUP BY tags.id
+		ORDER BY tags.name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (s *SQLiteStorage) TabsByTag(tag string) ([]models.Tab, error) {
+	query := `
+		SELECT ` + tabColumns + ` FROM tabs
+		WHERE deleted_at IS NULL AND id IN (
+			SELECT tab_id FROM tab_tags JOIN tags ON tags.id = tab_tags.tag_id
+			WHERE tags.name = ?
+		)
+		ORDER BY updated_at DESC
+	`
+	rows, err := s.db.Query(query, strings.TrimSpace(tag))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.withTags(scanTabs(rows))
+}
+
+func (s *SQLiteStorage) SetFavorite(tabID int, favorite bool) error {
+	_, err := s.db.Exec(`UPDATE tabs SET favorite = ? WHERE id = ?`, favorite, tabID)
+	return err
+}
+
+// withTags fills in the Tags of freshly scanned tabs.
+func (s *SQLiteStorage) withTags(tabs []models.Tab) ([]models.Tab, error) {
+	if len(tabs) == 0 {
+		return tabs, nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT tab_tags.tab_id, tags.name FROM tab_tags
+		JOIN tags ON tags.id = tab_tags.tag_id
+		ORDER BY tags.name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byTab := make(map[int][]string)
+	for rows.Next() {
+		var tabID int
+		var name string
+		if err := rows.Scan(&tabID, &name); err != nil {
+			continue
+		}
+		byTab[tabID] = append(byTab[tabID], name)
+	}
+
+	for i := range tabs {
+		tabs[i].Tags = byTab[tabs[i].ID]
+	}
+	return tabs, nil
+}
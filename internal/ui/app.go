@@ -2,13 +2,16 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/export"
 	"github.com/Cod-e-Codes/tuitar/internal/midi"
 	"github.com/Cod-e-Codes/tuitar/internal/models"
 	"github.com/Cod-e-Codes/tuitar/internal/storage"
@@ -21,6 +24,9 @@ const (
 	inputModeNone inputMode = iota
 	inputModeSave
 	inputModeRename
+	inputModeTags
+	inputModeImport
+	inputModeExport
 )
 
 type Model struct {
@@ -28,10 +34,14 @@ type Model struct {
 	storage    storage.Storage
 	tabs       []models.Tab
 	midiPlayer *midi.Player
+	macros     map[string][]string
+	wrap       bool
 
 	// Components
 	tabEditor  components.TabEditorModel
 	tabBrowser components.TabBrowserModel
+	history    components.HistoryModel
+	setlists   components.SetlistModel
 	statusBar  components.StatusBarModel
 	help       help.Model
 	textInput  textinput.Model
@@ -41,6 +51,21 @@ type Model struct {
 	showHelp   bool
 	inputMode  inputMode
 	keys       KeyMap
+	confirm    *confirmDialog
+	autosave   time.Duration
+	journalID  int // recovery journal entry for the current tab's unsaved edits
+
+	renameTarget  *models.Tab     // tab being renamed from the browser
+	tagTargets    []models.Tab    // tabs whose tags are being edited
+	knownTags     []models.Tag    // suggestions for the tag editor
+	historyReturn models.ViewMode // view to go back to from the history
+	setlistTarget int             // setlist that browser A adds tabs to
+	playingSongs  []int           // setlist entry of each song the player was given
+	exportTargets []models.Tab    // tabs being exported from the browser
+	exportOptions export.Options
+	exportFormat  export.Format
+	importTuning  int  // index in importTunings
+	warnedSearch  bool // told that search is without FTS5 this session
 }
 
 type KeyMap struct {
@@ -58,6 +83,8 @@ type KeyMap struct {
 	Normal    key.Binding
 	Browser   key.Binding
 	Delete    key.Binding
+	History   key.Binding
+	Setlists  key.Binding
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -69,7 +96,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Enter, k.Save, k.New},
 		{k.Insert, k.Normal, k.Browser},
-		{k.Play, k.Delete, k.Help, k.Quit},
+		{k.Play, k.Delete, k.History, k.Setlists, k.Help, k.Quit},
 	}
 }
 
@@ -100,8 +127,8 @@ func NewKeyMap() KeyMap {
 			key.WithHelp("q", "quit"),
 		),
 		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "toggle help"),
+			key.WithKeys("?", "f1"),
+			key.WithHelp("?/f1", "toggle help"),
 		),
 		Save: key.NewBinding(
 			key.WithKeys("ctrl+s"),
@@ -131,12 +158,27 @@ func NewKeyMap() KeyMap {
 			key.WithKeys("x"),
 			key.WithHelp("x", "delete fret"),
 		),
+		History: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "revision history"),
+		),
+		Setlists: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "setlists"),
+		),
 	}
 }
 
 func NewModel(storage storage.Storage) Model {
 	tabs, _ := storage.LoadAllTabs()
 
+	macros := make(map[string][]string)
+	if saved, err := storage.LoadMacros(); err == nil {
+		for _, macro := range saved {
+			macros[macro.Register] = macro.Keys
+		}
+	}
+
 	textInput := textinput.New()
 	textInput.Placeholder = "Enter tab name..."
 	textInput.Focus()
@@ -144,6 +186,7 @@ func NewModel(storage storage.Storage) Model {
 	m := Model{
 		storage:    storage,
 		tabs:       tabs,
+		macros:     macros,
 		keys:       NewKeyMap(),
 		help:       help.New(),
 		tabBrowser: components.NewTabBrowser(tabs),
@@ -155,11 +198,46 @@ func NewModel(storage storage.Storage) Model {
 	m.state.ViewMode = models.ViewBrowser
 	m.state.EditMode = models.EditNormal
 
+	if saved, err := storage.GetSetting(browserPrefsKey); err == nil && saved != "" {
+		prefs := components.DefaultBrowserPrefs()
+		if json.Unmarshal([]byte(saved), &prefs) == nil {
+			m.tabBrowser.SetPrefs(prefs)
+		}
+	}
+
+	m.exportOptions.ASCII = export.DefaultASCIIOptions()
+	if saved, err := storage.GetSetting(exportPrefsKey); err == nil && saved != "" {
+		json.Unmarshal([]byte(saved), &m.exportOptions.ASCII)
+	}
+	if saved, err := storage.GetSetting(lilyPondPrefsKey); err == nil && saved != "" {
+		json.Unmarshal([]byte(saved), &m.exportOptions.LilyPond)
+	}
+	m.exportFormat = export.FormatText
+	if saved, err := storage.GetSetting(exportFormatKey); err == nil {
+		if format, err := export.ParseFormat(saved); err == nil {
+			m.exportFormat = format
+		}
+	}
+
+	if saved, err := storage.GetSetting(importTuningKey); err == nil {
+		for i, tuning := range importTunings {
+			if tuning.tuning == saved {
+				m.importTuning = i
+			}
+		}
+	}
+
+	// Edits left in the journal belong to sessions that never saved or
+	// discarded them, most likely because the terminal died
+	if entries, err := storage.LoadJournal(); err == nil {
+		m.offerRecovery(entries)
+	}
+
 	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.SetWindowTitle("Tuitar - Guitar Tab TUI")
+	return tea.Batch(tea.SetWindowTitle("Tuitar - Guitar Tab TUI"), m.autosaveTick())
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -170,19 +248,97 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.windowSize = msg
 		m.tabEditor.SetSize(msg.Width, msg.Height-3)
 		m.tabBrowser.SetSize(msg.Width, msg.Height-3)
+		m.history.SetSize(msg.Width, msg.Height-5)
+		m.setlists.SetSize(msg.Width, msg.Height-5)
+
+	case autosaveMsg:
+		return m.handleAutosave()
+
+	case components.FilterChangedMsg:
+		if !m.warnedSearch && !m.storage.FullTextSearch() {
+			m.statusBar.SetStatus("Search matches plain text only: this build lacks FTS5 (build with -tags sqlite_fts5)")
+			m.warnedSearch = true
+		}
+		return m, m.searchTabs(msg.Query)
+
+	case components.BrowserPrefsChangedMsg:
+		m.saveBrowserPrefs(msg.Prefs)
+		return m, nil
+
+	case components.BrowserActionMsg:
+		return m.handleBrowserAction(msg)
+
+	case components.RestoreRevisionMsg:
+		return m.restoreRevision(msg.Revision)
+
+	case components.HistoryClosedMsg:
+		m.state.ViewMode = m.historyReturn
+		return m, nil
+
+	case components.SetlistChangedMsg:
+		m.saveSetlist(msg.Setlist)
+		return m, nil
+
+	case components.SetlistActionMsg:
+		return m.handleSetlistAction(msg)
+
+	case components.SetlistClosedMsg:
+		m.state.ViewMode = models.ViewBrowser
+		return m, nil
+
+	case setlistTickMsg:
+		return m, m.updatePlayingSong()
+
+	case components.TrashToggledMsg:
+		m.refreshTabs()
+		if msg.Trash {
+			m.statusBar.SetStatus("Trash: u restores, dd deletes forever")
+		} else {
+			m.statusBar.SetStatus("Library")
+		}
+		return m, nil
+
+	case components.SearchResultsMsg:
+		var cmd tea.Cmd
+		m.tabBrowser, cmd = m.tabBrowser.Update(msg)
+		return m, cmd
 
 	case tea.KeyMsg:
+		// An unsaved-changes prompt takes every key
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+
 		// Handle input mode first
 		if m.inputMode != inputModeNone {
 			return m.updateInput(msg)
 		}
 
+		if m.state.ViewMode == models.ViewHistory && msg.String() != "ctrl+c" {
+			var cmd tea.Cmd
+			m.history, cmd = m.history.Update(msg)
+			return m, cmd
+		}
+		if m.state.ViewMode == models.ViewSetlist && msg.String() != "ctrl+c" {
+			var cmd tea.Cmd
+			m.setlists, cmd = m.setlists.Update(msg)
+			return m, cmd
+		}
+		if m.state.ViewMode == models.ViewEditor && m.editorWantsKey(msg) {
+			return m.updateEditor(msg)
+		}
+		if m.state.ViewMode == models.ViewBrowser && m.tabBrowser.Filtering() {
+			return m.updateBrowser(msg)
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Quit):
-			if m.midiPlayer.IsPlaying() {
-				m.midiPlayer.Stop()
-			}
-			return m, tea.Quit
+			return m.guardUnsaved("Save changes before quitting?", func(m *Model) tea.Cmd {
+				if m.midiPlayer.IsPlaying() {
+					m.midiPlayer.Stop()
+				}
+				return tea.Quit
+			})
 
 		case key.Matches(msg, m.keys.Help):
 			m.showHelp = !m.showHelp
@@ -200,14 +356,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, m.keys.New):
-			newTab := models.NewEmptyTab("New Tab")
-			m.state.CurrentTab = newTab
-			m.tabEditor = components.NewTabEditor(newTab)
-			m.tabEditor.SetEditMode(models.EditNormal)
-			m.state.ViewMode = models.ViewEditor
-			m.state.EditMode = models.EditNormal
-			m.statusBar.SetStatus("Created new tab")
-			return m, nil
+			return m.guardUnsaved("Save changes before creating a new tab?", func(m *Model) tea.Cmd {
+				m.openEditor(models.NewEmptyTab("New Tab"))
+				m.statusBar.SetStatus("Created new tab")
+				return nil
+			})
 
 		case key.Matches(msg, m.keys.Save):
 			if m.state.CurrentTab != nil {
@@ -252,25 +405,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// editorWantsKey reports whether a key that would otherwise be a global
+// shortcut belongs to the editor: q records macros, ? searches backwards,
+// and a search prompt takes every key. Quit with Ctrl+C and open help with
+// F1 from the editor.
+func (m Model) editorWantsKey(msg tea.KeyMsg) bool {
+	if m.tabEditor.Searching() {
+		return true
+	}
+	if msg.String() == "ctrl+c" {
+		return false
+	}
+	return key.Matches(msg, m.keys.Quit) || key.Matches(msg, m.keys.Help)
+}
+
 func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.inputMode == inputModeExport && m.updateExportOptions(msg.String()) {
+		return m, nil
+	}
+	if m.inputMode == inputModeImport && m.updateImportOptions(msg.String()) {
+		return m, nil
+	}
+
 	switch msg.Type {
 	case tea.KeyEsc:
-		m.inputMode = inputModeNone
-		m.textInput.Blur()
+		m.closeInput()
 		return m, nil
 
 	case tea.KeyEnter:
 		value := m.textInput.Value()
-		if value != "" {
-			switch m.inputMode {
-			case inputModeSave:
-				m.state.CurrentTab.Name = value
-				m.saveCurrentTab()
-			}
+		switch {
+		case m.inputMode == inputModeTags:
+			// An empty list clears the tags
+			m.applyTags(value)
+		case value == "":
+		case m.inputMode == inputModeSave:
+			m.state.CurrentTab.Name = value
+			m.saveCurrentTab()
+		case m.inputMode == inputModeRename:
+			m.renameTab(value)
+		case m.inputMode == inputModeImport:
+			m.closeInput()
+			m.importFile(value)
+			return m, nil
+		case m.inputMode == inputModeExport:
+			m.exportTabs(value)
 		}
-		m.inputMode = inputModeNone
-		m.textInput.Blur()
-		m.textInput.SetValue("")
+		m.closeInput()
 		return m, nil
 	}
 
@@ -279,36 +460,138 @@ func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m *Model) saveCurrentTab() {
+func (m *Model) closeInput() {
+	m.inputMode = inputModeNone
+	m.renameTarget = nil
+	m.tagTargets = nil
+	m.exportTargets = nil
+	m.textInput.Blur()
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "Enter tab name..."
+}
+
+func (m *Model) saveCurrentTab() bool {
 	err := m.storage.SaveTab(m.state.CurrentTab)
 	if err != nil {
 		m.statusBar.SetStatus("Error saving tab: " + err.Error())
-	} else {
-		m.statusBar.SetStatus("Tab saved: " + m.state.CurrentTab.Name)
-		// Refresh tabs list
-		if tabs, err := m.storage.LoadAllTabs(); err == nil {
-			m.tabs = tabs
-			m.tabBrowser.SetTabs(tabs)
+		return false
+	}
+
+	m.markSaved()
+	m.statusBar.SetStatus("Tab saved: " + m.state.CurrentTab.Name)
+	return true
+}
+
+func (m *Model) refreshTabs() {
+	tabs, err := m.storage.LoadAllTabs()
+	if err != nil {
+		return
+	}
+	m.tabs = tabs
+
+	if m.tabBrowser.Trash() {
+		tabs, err = m.storage.LoadTrash()
+		if err != nil {
+			m.statusBar.SetStatus("Error loading trash: " + err.Error())
+			return
+		}
+	}
+	m.tabBrowser.SetTabs(tabs)
+}
+
+// openEditor makes tab the current tab and switches to a fresh editor for it,
+// carrying over the window size, macro registers and wrap preference.
+func (m *Model) openEditor(tab *models.Tab) {
+	m.state.CurrentTab = tab
+	m.tabEditor = components.NewTabEditor(tab)
+	m.tabEditor.SetMacros(m.macros)
+	m.tabEditor.SetWrap(m.wrap)
+	m.tabEditor.SetEditMode(models.EditNormal)
+	if m.windowSize.Width > 0 {
+		m.tabEditor.SetSize(m.windowSize.Width, m.windowSize.Height-3)
+	}
+	m.state.ViewMode = models.ViewEditor
+	m.state.EditMode = models.EditNormal
+	m.state.Modified = false
+	m.journalID = 0
+}
+
+// searchTabs runs Storage.SearchTabs for the browser filter in the
+// background, so full-text matches on notes and riffs join the list.
+func (m Model) searchTabs(query string) tea.Cmd {
+	store := m.storage
+	return func() tea.Msg {
+		tabs, err := store.SearchTabs(query)
+		if err != nil {
+			return nil
 		}
+		return components.SearchResultsMsg{Query: query, Tabs: tabs}
 	}
 }
 
+// browserPrefsKey is the setting holding the browser's sort and grouping.
+const browserPrefsKey = "browser.prefs"
+
+func (m *Model) saveBrowserPrefs(prefs components.BrowserPrefs) {
+	data, _ := json.Marshal(prefs)
+	if err := m.storage.SetSetting(browserPrefsKey, string(data)); err != nil {
+		m.statusBar.SetStatus("Error saving browser settings: " + err.Error())
+		return
+	}
+
+	status := "Sorted by " + prefs.Sort
+	if prefs.Desc {
+		status += " (descending)"
+	}
+	if prefs.Group != "" {
+		status += ", grouped by " + prefs.Group
+	}
+	m.statusBar.SetStatus(status)
+}
+
 func (m Model) updateBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.tabBrowser.Filtering() {
+		m.tabBrowser, cmd = m.tabBrowser.Update(msg)
+		return m, cmd
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Enter):
-		if len(m.tabs) > 0 && m.tabBrowser.Cursor() < len(m.tabs) {
-			selectedTab := &m.tabs[m.tabBrowser.Cursor()]
-			tabCopy := *selectedTab
-			m.state.CurrentTab = &tabCopy
-			m.tabEditor = components.NewTabEditor(&tabCopy)
-			m.tabEditor.SetEditMode(models.EditNormal)
-			m.state.ViewMode = models.ViewEditor
-			m.state.EditMode = models.EditNormal
-			m.statusBar.SetStatus("Editing: " + tabCopy.Name)
+		if selected := m.tabBrowser.Selected(); selected != nil && m.tabBrowser.Trash() {
+			m.statusBar.SetStatus("Restore " + selected.Name + " with u to edit it")
+			return m, nil
+		}
+		if selected := m.tabBrowser.Selected(); selected != nil {
+			tabCopy := *selected
+			if current := m.state.CurrentTab; current != nil && current.ID == tabCopy.ID {
+				// Reopening the tab being edited keeps its unsaved edits
+				m.state.ViewMode = models.ViewEditor
+				return m, nil
+			}
+			return m.guardUnsaved("Save changes before opening "+tabCopy.Name+"?", func(m *Model) tea.Cmd {
+				m.openEditor(&tabCopy)
+				m.statusBar.SetStatus("Editing: " + tabCopy.Name)
+				return nil
+			})
+		}
+		// Enter on a group header expands or collapses it
+
+	case key.Matches(msg, m.keys.History):
+		if selected := m.tabBrowser.Selected(); selected != nil && !m.tabBrowser.Trash() {
+			m.openHistory(selected)
 		}
 		return m, nil
+
+	case key.Matches(msg, m.keys.Setlists):
+		return m, m.openSetlists()
+
+	case msg.String() == "I" && !m.tabBrowser.Trash():
+		m.inputMode = inputModeImport
+		m.textInput.Placeholder = "path/to/tab.txt"
+		m.textInput.Focus()
+		return m, nil
 	}
 
 	m.tabBrowser, cmd = m.tabBrowser.Update(msg)
@@ -318,26 +601,54 @@ func (m Model) updateBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
-	switch {
-	case key.Matches(msg, m.keys.Insert):
-		m.state.EditMode = models.EditInsert
-		m.tabEditor.SetEditMode(models.EditInsert)
-		m.statusBar.SetStatus("-- INSERT MODE --")
-		return m, nil
-
-	case key.Matches(msg, m.keys.Normal):
-		m.state.EditMode = models.EditNormal
-		m.tabEditor.SetEditMode(models.EditNormal)
-		m.statusBar.SetStatus("-- NORMAL MODE --")
+	if key.Matches(msg, m.keys.History) && m.state.EditMode == models.EditNormal &&
+		!m.tabEditor.Searching() && m.tabEditor.Recording() == "" {
+		m.openHistory(m.state.CurrentTab)
 		return m, nil
 	}
 
-	// Pass the message to the tab editor
+	// Pass the message to the tab editor. Mode switching happens inside the
+	// editor so that recorded macros can replay it.
+	wasRecording := m.tabEditor.Recording()
 	m.tabEditor, cmd = m.tabEditor.Update(msg)
-	
+
+	if mode := m.tabEditor.GetEditMode(); mode != m.state.EditMode {
+		m.state.EditMode = mode
+		if mode == models.EditInsert {
+			m.statusBar.SetStatus("-- INSERT MODE --")
+		} else {
+			m.statusBar.SetStatus("-- NORMAL MODE --")
+		}
+	}
+
+	if wrap := m.tabEditor.Wrap(); wrap != m.wrap {
+		m.wrap = wrap
+		if wrap {
+			m.statusBar.SetStatus("System wrap on")
+		} else {
+			m.statusBar.SetStatus("System wrap off")
+		}
+	}
+
+	if reg := m.tabEditor.Recording(); reg != "" && wasRecording == "" {
+		m.statusBar.SetStatus("Recording @" + reg)
+	}
+
+	if reg, keys, ok := m.tabEditor.TakeRecordedMacro(); ok {
+		macro := &models.Macro{Register: reg, Keys: keys}
+		if err := m.storage.SaveMacro(macro); err != nil {
+			m.statusBar.SetStatus("Error saving macro: " + err.Error())
+		} else {
+			m.statusBar.SetStatus(fmt.Sprintf("Recorded @%s (%d keys)", reg, len(keys)))
+		}
+	}
+
 	// Update the current tab if it has changed
 	if m.tabEditor.HasChanged() {
 		m.state.CurrentTab = m.tabEditor.GetTab()
+		m.state.Modified = true
+		m.tabEditor.ResetChanged()
+		m.writeJournal()
 	}
 
 	return m, cmd
@@ -345,6 +656,9 @@ func (m Model) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m Model) View() string {
 	// Handle input dialogs
+	if m.confirm != nil {
+		return m.renderConfirmDialog()
+	}
 	if m.inputMode != inputModeNone {
 		return m.renderInputDialog()
 	}
@@ -359,6 +673,10 @@ func (m Model) View() string {
 		content = m.renderBrowser()
 	case models.ViewEditor:
 		content = m.renderEditor()
+	case models.ViewHistory:
+		content = m.renderHistory()
+	case models.ViewSetlist:
+		content = m.renderSetlists()
 	}
 
 	statusBar := m.statusBar.View()
@@ -372,20 +690,54 @@ func (m Model) renderInputDialog() string {
 		title = "Save Tab As:"
 	case inputModeRename:
 		title = "Rename Tab:"
+	case inputModeImport:
+		title = "Import Tab From File:"
+	case inputModeExport:
+		title = "Export " + m.exportTargets[0].Name + " To:"
+		if len(m.exportTargets) > 1 {
+			title = fmt.Sprintf("Export %d Tabs To Directory:", len(m.exportTargets))
+		}
+	case inputModeTags:
+		title = "Tags of " + m.tagTargets[0].Name + ":"
+		if len(m.tagTargets) > 1 {
+			title = fmt.Sprintf("Add tags to %d tabs (-tag removes):", len(m.tagTargets))
+		}
 	}
 
+	hints := "Enter: Save • Esc: Cancel"
+	if m.inputMode == inputModeTags {
+		hints = "Separate tags with commas • " + hints
+	}
+	if m.inputMode == inputModeImport {
+		hints = "Enter: Import • Esc: Cancel"
+	}
+	if m.inputMode == inputModeExport {
+		hints = "Enter: Export • Esc: Cancel"
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render(title),
+		"",
+		m.textInput.View(),
+		"",
+	}
+	if m.inputMode == inputModeTags && len(m.knownTags) > 0 {
+		lines = append(lines, m.knownTagsLine(), "")
+	}
+	if m.inputMode == inputModeImport {
+		lines = append(lines, m.importOptionsLine(), "")
+	}
+	if m.inputMode == inputModeExport {
+		lines = append(lines, m.exportOptionsLine(), "")
+	}
+	lines = append(lines, lipgloss.NewStyle().Faint(true).Render(hints))
+
 	dialog := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("12")).
 		Padding(1, 2).
 		Width(50).
-		Render(lipgloss.JoinVertical(lipgloss.Left,
-			lipgloss.NewStyle().Bold(true).Render(title),
-			"",
-			m.textInput.View(),
-			"",
-			lipgloss.NewStyle().Faint(true).Render("Enter: Save • Esc: Cancel"),
-		))
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
 
 	return lipgloss.Place(m.windowSize.Width, m.windowSize.Height, 
 		lipgloss.Center, lipgloss.Center, dialog)
@@ -398,8 +750,8 @@ func (m Model) renderHelp() string {
 			lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")).Render("Tuitar - Guitar Tab Editor Help"),
 			"",
 			lipgloss.NewStyle().Bold(true).Render("Global Keys:"),
-			"  q, Ctrl+C     - Quit application",
-			"  ?             - Toggle this help",
+			"  q, Ctrl+C     - Quit application (Ctrl+C in editor)",
+			"  ?, F1         - Toggle this help (F1 in editor)",
 			"  Ctrl+N        - Create new tab",
 			"  Ctrl+S        - Save current tab",
 			"  Tab           - Switch between browser and editor",
@@ -407,6 +759,28 @@ func (m Model) renderHelp() string {
 			lipgloss.NewStyle().Bold(true).Render("Browser Mode:"),
 			"  ↑/k, ↓/j      - Navigate tab list",
 			"  Enter         - Edit selected tab",
+			"  /             - Filter by name, artist, tuning or tags",
+			"                  #tag keeps only tabs with a matching tag",
+			"                  also lyrics/notes and riffs (7-9-7, G:7-9-7)",
+			"  Esc           - Clear the filter",
+			"  s, S          - Cycle sort column, reverse order",
+			"  g             - Group by none/artist/tuning/tag",
+			"  p             - Toggle the preview pane",
+			"  Enter, h, l   - Toggle, collapse, expand group",
+			"  v, V          - Mark tab, mark all/clear marks",
+			"  dd            - Delete marked or selected tabs",
+			"  yy            - Duplicate marked or selected tabs",
+			"  r             - Rename selected tab",
+			"  T             - Edit tags of marked or selected tabs",
+			"  f, F          - Toggle favorite, show favorites only",
+			"  t             - Show the trash / back to the library",
+			"  u             - Restore marked or selected tabs (trash)",
+			"  dd            - Delete forever (trash)",
+			"  A             - Add marked or selected tabs to a setlist",
+			"  I             - Import a plain-text, Guitar Pro, MusicXML, MIDI or ChordPro file",
+			"  x             - Export marked or selected tabs (Ctrl+F picks the format)",
+			"                  Tab, Ctrl+N, Ctrl+T set width, measure numbers, header",
+			"  L             - Setlists",
 			"",
 			lipgloss.NewStyle().Bold(true).Render("Editor Mode - Normal:"),
 			"  ↑/k, ↓/j      - Move between strings",
@@ -414,6 +788,35 @@ func (m Model) renderHelp() string {
 			"  i             - Enter insert mode",
 			"  x             - Delete fret (replace with -)",
 			"  Space         - Play/pause tab",
+			"  [count]h/j/k/l/x - Repeat motion or delete",
+			"  q<a-z> ... q  - Record macro into register",
+			"  [count]@<a-z> - Replay macro (@@ repeats last)",
+			"  /pat, ?pat    - Search forward/backward for frets",
+			"                  7  7-9-7  G:7-9-7  x32010",
+			"  n, N          - Next/previous match",
+			"  W             - Toggle system wrap at measures",
+			"  H             - Revision history (also in the browser)",
+			"",
+			lipgloss.NewStyle().Bold(true).Render("History:"),
+			"  ↑/k, ↓/j      - Select revision",
+			"  b             - Compare other revisions with this one",
+			"  J, K          - Scroll the diff",
+			"  Enter, r      - Restore the selected revision",
+			"  Esc, q        - Back",
+			"",
+			lipgloss.NewStyle().Bold(true).Render("Setlists:"),
+			"  ↑/k, ↓/j      - Select setlist or song",
+			"  Enter/l, h    - Edit the songs, back to the setlists",
+			"  n, r, dd      - New, rename, delete setlist",
+			"  a             - Add songs from the browser (mark, then A)",
+			"  J, K          - Move song down/up",
+			"  dd            - Remove song",
+			"  e, Enter      - Edit the song's notes",
+			"  c/C, +/-, </> - Capo, transpose, tempo; R resets them",
+			"  g, G          - Shorter/longer gap between songs",
+			"  p, s          - Play from the song/start, stop",
+			"  x             - Export as one text file",
+			"  Esc, q        - Back",
 			"",
 			lipgloss.NewStyle().Bold(true).Render("Editor Mode - Insert:"),
 			"  0-9           - Insert fret number (auto-advance)",
@@ -422,21 +825,28 @@ func (m Model) renderHelp() string {
 			"  Esc           - Return to normal mode",
 			"  Arrow keys    - Navigate",
 			"",
-			lipgloss.NewStyle().Faint(true).Render("Press ? again to close this help"),
+			lipgloss.NewStyle().Faint(true).Render("Press ? or F1 again to close this help"),
 		))
 
 	return helpContent
 }
 
 func (m Model) renderBrowser() string {
+	heading := "Tuitar - Guitar Tab Browser"
+	hints := "Enter: Edit • /: Filter • s/S: Sort • g: Group • v: Mark • dd: Delete • yy: Copy • r: Rename • T: Tags • f: Favorite • A: Add to setlist • L: Setlists • I: Import • x: Export • t: Trash • ?: Help • Q: Quit"
+	if m.tabBrowser.Trash() {
+		heading = "Tuitar - Trash"
+		hints = "u: Restore • dd: Delete forever • v: Mark • /: Filter • s/S: Sort • t: Library • ?: Help • Q: Quit"
+	}
+
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("12")).
-		Render("Tuitar - Guitar Tab Browser")
+		Render(heading)
 
 	help := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("8")).
-		Render("Enter: Edit • Ctrl+N: New • Tab: Editor • ?: Help • Q: Quit")
+		Render(hints)
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		title,
@@ -452,10 +862,15 @@ func (m Model) renderEditor() string {
 		return "No tab selected"
 	}
 
+	modifiedMarker := ""
+	if m.state.Modified {
+		modifiedMarker = " [+]"
+	}
+
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("12")).
-		Render(fmt.Sprintf("Editing: %s", m.state.CurrentTab.Name))
+		Render(fmt.Sprintf("Editing: %s%s", m.state.CurrentTab.Name, modifiedMarker))
 
 	// Show playback status
 	playStatus := ""
@@ -477,6 +892,12 @@ func (m Model) renderEditor() string {
 		Foreground(modeColor).
 		Render(fmt.Sprintf("-- %s --", mode)) + playStatus
 
+	if reg := m.tabEditor.Recording(); reg != "" {
+		modeIndicator += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")).
+			Render(" recording @" + reg)
+	}
+
 	var help string
 	if m.state.EditMode == models.EditInsert {
 		help = lipgloss.NewStyle().
@@ -485,7 +906,7 @@ func (m Model) renderEditor() string {
 	} else {
 		help = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("8")).
-			Render("I: Insert • X: Delete • Space: Play • Ctrl+S: Save • Tab: Browser • Arrows: Navigate")
+			Render("I: Insert • X: Delete • Space: Play • Ctrl+S: Save • Tab: Browser • Q: Record • @: Replay")
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left,
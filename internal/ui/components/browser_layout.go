@@ -0,0 +1,375 @@
+// internal/ui/components/browser_layout.go
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// SortColumns are the browser sort keys, in the order "s" cycles through them.
+var SortColumns = []string{"updated", "name", "artist", "tuning", "tempo", "created", "length"}
+
+// GroupModes are the browser groupings, in the order "g" cycles through them.
+var GroupModes = []string{"", "artist", "tuning", "tag"}
+
+// BrowserPrefs is the browser's sort and grouping, persisted between
+// sessions by the parent.
+type BrowserPrefs struct {
+	Sort  string `json:"sort"`
+	Desc  bool   `json:"desc"`
+	Group string `json:"group"`
+}
+
+// DefaultBrowserPrefs lists recently updated tabs first, ungrouped.
+func DefaultBrowserPrefs() BrowserPrefs {
+	return BrowserPrefs{Sort: "updated", Desc: true}
+}
+
+// BrowserPrefsChangedMsg is emitted when the user changes sort or grouping.
+type BrowserPrefsChangedMsg struct {
+	Prefs BrowserPrefs
+}
+
+// browserRow is one line of the list: a tab or a group header.
+type browserRow struct {
+	item  int // index into items, -1 for a group header
+	group string
+	count int // tabs in the group, for headers
+}
+
+type column struct {
+	title string
+	sort  string // SortColumns key, "" if not sortable
+	width int
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func nextOf(list []string, s string) string {
+	return list[(indexOf(list, s)+1)%len(list)]
+}
+
+// SetPrefs applies saved preferences, ignoring unknown values.
+func (m *TabBrowserModel) SetPrefs(prefs BrowserPrefs) {
+	if indexOf(SortColumns, prefs.Sort) < 0 {
+		prefs.Sort = DefaultBrowserPrefs().Sort
+	}
+	if indexOf(GroupModes, prefs.Group) < 0 {
+		prefs.Group = ""
+	}
+	m.prefs = prefs
+	m.refresh()
+}
+
+func (m TabBrowserModel) Prefs() BrowserPrefs {
+	return m.prefs
+}
+
+func compareTabs(a, b *models.Tab, key string) int {
+	switch key {
+	case "name":
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case "artist":
+		return strings.Compare(strings.ToLower(a.Artist), strings.ToLower(b.Artist))
+	case "tuning":
+		return strings.Compare(a.TuningString(), b.TuningString())
+	case "tempo":
+		return a.Tempo - b.Tempo
+	case "created":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "length":
+		return a.Length() - b.Length()
+	default:
+		// In the trash the date column shows when tabs were deleted
+		if a.DeletedAt != nil && b.DeletedAt != nil {
+			return a.DeletedAt.Compare(*b.DeletedAt)
+		}
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+}
+
+// groupKeys returns the groups a tab is listed under. With tag grouping a
+// tab appears once under each of its tags.
+func groupKeys(tab *models.Tab, mode string) []string {
+	switch mode {
+	case "artist":
+		if tab.Artist == "" {
+			return []string{"(no artist)"}
+		}
+		return []string{tab.Artist}
+	case "tuning":
+		return []string{tab.TuningString()}
+	case "tag":
+		if len(tab.Tags) == 0 {
+			return []string{"(untagged)"}
+		}
+		return tab.Tags
+	}
+	return []string{""}
+}
+
+// sortItems orders unfiltered items by the sort column. Filtered items keep
+// their relevance order.
+func (m *TabBrowserModel) sortItems() {
+	if m.filter != "" {
+		return
+	}
+	sort.SliceStable(m.items, func(i, j int) bool {
+		c := compareTabs(&m.items[i].tab, &m.items[j].tab, m.prefs.Sort)
+		if m.prefs.Desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// buildRows lays the items out as rows, under collapsible group headers when
+// grouping is on and no filter is active.
+func (m *TabBrowserModel) buildRows() {
+	m.rows = nil
+
+	if m.prefs.Group == "" || m.filter != "" {
+		for i := range m.items {
+			m.rows = append(m.rows, browserRow{item: i})
+		}
+		return
+	}
+
+	members := make(map[string][]int)
+	var groups []string
+	for i := range m.items {
+		for _, key := range groupKeys(&m.items[i].tab, m.prefs.Group) {
+			if _, ok := members[key]; !ok {
+				groups = append(groups, key)
+			}
+			members[key] = append(members[key], i)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i]) < strings.ToLower(groups[j])
+	})
+
+	for _, g := range groups {
+		m.rows = append(m.rows, browserRow{item: -1, group: g, count: len(members[g])})
+		if m.collapsed[g] {
+			continue
+		}
+		for _, i := range members[g] {
+			m.rows = append(m.rows, browserRow{item: i, group: g})
+		}
+	}
+}
+
+// refresh recomputes the visible rows, keeping the cursor on the same tab
+// or group header when it is still listed.
+func (m *TabBrowserModel) refresh() {
+	var keepID int
+	var keepGroup string
+	if m.cursor < len(m.rows) {
+		if row := m.rows[m.cursor]; row.item >= 0 && row.item < len(m.items) {
+			keepID = m.items[row.item].tab.ID
+		} else {
+			keepGroup = row.group
+		}
+	}
+
+	m.applyFilter()
+	m.sortItems()
+	m.buildRows()
+
+	for i, row := range m.rows {
+		if (row.item >= 0 && keepID != 0 && m.items[row.item].tab.ID == keepID) ||
+			(row.item < 0 && keepGroup != "" && row.group == keepGroup) {
+			m.cursor = i
+			break
+		}
+	}
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m TabBrowserModel) prefsChanged() (TabBrowserModel, tea.Cmd) {
+	m.refresh()
+	prefs := m.prefs
+	return m, func() tea.Msg {
+		return BrowserPrefsChangedMsg{Prefs: prefs}
+	}
+}
+
+// columns sizes the list columns for the current width. Name, artist and
+// tags share whatever the fixed columns leave; Created only shows on wide
+// screens.
+func (m TabBrowserModel) columns() []column {
+	width := m.listWidth()
+	if width <= 0 {
+		width = 80
+	}
+
+	fixed := []column{
+		{title: "ID", width: 5},
+		{title: "Name", sort: "name"},
+		{title: "Artist", sort: "artist"},
+		{title: "Tags"},
+		{title: "Tuning", sort: "tuning", width: 11},
+		{title: "Tempo", sort: "tempo", width: 5},
+		{title: "Bars", sort: "length", width: 4},
+		{title: "Updated", sort: "updated", width: 10},
+	}
+	if m.trash {
+		fixed[len(fixed)-1].title = "Deleted"
+	}
+	if width >= 110 {
+		fixed = append(fixed, column{title: "Created", sort: "created", width: 10})
+	}
+
+	used := len(fixed) - 1 // one space between columns
+	for _, c := range fixed {
+		used += c.width
+	}
+	rest := width - used
+	if rest < 16 {
+		rest = 16
+	}
+	fixed[1].width = rest * 2 / 5
+	fixed[2].width = rest * 3 / 10
+	fixed[3].width = rest - fixed[1].width - fixed[2].width
+
+	return fixed
+}
+
+func (m TabBrowserModel) headerLine() string {
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("8"))
+
+	var cells []string
+	for _, c := range m.columns() {
+		title := c.title
+		if c.sort != "" && c.sort == m.prefs.Sort {
+			if m.prefs.Desc {
+				title += "▼"
+			} else {
+				title += "▲"
+			}
+		}
+		cells = append(cells, fitCell(title, nil, c.width, style, style))
+	}
+
+	line := strings.Join(cells, style.Render(" "))
+	if m.prefs.Group != "" && m.filter == "" {
+		line += style.Render("  by " + m.prefs.Group)
+	}
+	if m.favorites {
+		line += style.Render("  ★ only")
+	}
+	if len(m.marked) > 0 {
+		line += style.Render(fmt.Sprintf("  %d marked", len(m.marked)))
+	}
+	return line
+}
+
+func (m TabBrowserModel) renderItem(item browserItem, style lipgloss.Style) string {
+	markStyle := style.Foreground(lipgloss.Color("11")).Bold(true).Underline(true)
+	tab := item.tab
+
+	name := tab.Name
+	nameMarks := item.marks[fieldName]
+	if tab.Favorite {
+		name = "★ " + name
+		nameMarks = shiftMarks(nameMarks, 2)
+	}
+	if item.fullText {
+		name += " [content match]"
+	}
+
+	var cells []string
+	for _, c := range m.columns() {
+		var cell string
+		switch c.title {
+		case "ID":
+			id := fmt.Sprintf("%d", tab.ID)
+			if m.marked[tab.ID] {
+				id = "●" + id
+			}
+			cell = fitCell(id, nil, c.width, style, markStyle)
+		case "Name":
+			cell = fitCell(name, nameMarks, c.width, style, markStyle)
+		case "Artist":
+			cell = fitCell(tab.Artist, item.marks[fieldArtist], c.width, style, markStyle)
+		case "Tags":
+			cell = fitCell(strings.Join(tab.Tags, " "), item.marks[fieldTags], c.width, style, markStyle)
+		case "Tuning":
+			cell = fitCell(tab.TuningString(), item.marks[fieldTuning], c.width, style, markStyle)
+		case "Tempo":
+			cell = fitCell(fmt.Sprintf("%d", tab.Tempo), nil, c.width, style, markStyle)
+		case "Bars":
+			cell = fitCell(fmt.Sprintf("%d", len(tab.Measures())), nil, c.width, style, markStyle)
+		case "Updated":
+			cell = fitCell(tab.UpdatedAt.Format("2006-01-02"), nil, c.width, style, markStyle)
+		case "Deleted":
+			var deleted string
+			if tab.DeletedAt != nil {
+				deleted = tab.DeletedAt.Format("2006-01-02")
+			}
+			cell = fitCell(deleted, nil, c.width, style, markStyle)
+		case "Created":
+			cell = fitCell(tab.CreatedAt.Format("2006-01-02"), nil, c.width, style, markStyle)
+		}
+		cells = append(cells, cell)
+	}
+
+	return strings.Join(cells, style.Render(" "))
+}
+
+func (m TabBrowserModel) renderGroupHeader(row browserRow, style lipgloss.Style) string {
+	arrow := "▾"
+	if m.collapsed[row.group] {
+		arrow = "▸"
+	}
+	return style.Bold(true).Foreground(lipgloss.Color("14")).
+		Render(fmt.Sprintf("%s %s (%d)", arrow, row.group, row.count))
+}
+
+func shiftMarks(marks []int, by int) []int {
+	shifted := make([]int, len(marks))
+	for i, pos := range marks {
+		shifted[i] = pos + by
+	}
+	return shifted
+}
+
+// fitCell renders text padded or truncated to width runes, with the runes
+// at marks in markStyle.
+func fitCell(text string, marks []int, width int, style, markStyle lipgloss.Style) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		if width < 1 {
+			return ""
+		}
+		runes = append(runes[:width-1], '…')
+	}
+
+	var kept []int
+	for _, i := range marks {
+		if i < len(runes) {
+			kept = append(kept, i)
+		}
+	}
+
+	return highlightMarks(string(runes), kept, style, markStyle) +
+		style.Render(strings.Repeat(" ", width-len(runes)))
+}
@@ -0,0 +1,48 @@
+// internal/ui/components/fuzzy.go
+package components
+
+import (
+	"unicode"
+)
+
+// fuzzyMatch reports whether every rune of pattern appears in text in order,
+// ignoring case. The score rewards consecutive runs and matches at the start
+// of words, so "gnr" ranks "Guns N' Roses" above "Gone Nowhere Rapidly".
+// positions holds the rune indexes of text that matched.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	t := []rune(text)
+
+	pi := 0
+	prev := -2
+	for ti, r := range t {
+		if pi == len(p) {
+			break
+		}
+		if unicode.ToLower(r) != unicode.ToLower(p[pi]) {
+			continue
+		}
+
+		score++
+		if ti == prev+1 {
+			score += 3
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 2
+		}
+		positions = append(positions, ti)
+		prev = ti
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, nil, false
+	}
+
+	// Prefer tighter matches in shorter fields
+	score -= (positions[len(positions)-1] - positions[0] + 1 - len(p)) / 2
+	return score, positions, true
+}
@@ -0,0 +1,341 @@
+// internal/ui/components/history.go
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// RestoreRevisionMsg asks the parent to make a revision the current version
+// of its tab.
+type RestoreRevisionMsg struct {
+	Revision models.Revision
+}
+
+// HistoryClosedMsg is emitted when the user leaves the history view.
+type HistoryClosedMsg struct{}
+
+// HistoryModel lists a tab's revisions and shows what changed between the
+// one under the cursor and an older one: by default the revision before it,
+// or a base picked with "b".
+type HistoryModel struct {
+	tab       models.Tab
+	revisions []models.Revision // newest first
+	cursor    int
+	base      int // index of the revision to compare with, -1 for the previous one
+	scroll    int // first diff line shown
+	width     int
+	height    int
+}
+
+// historyListRows is how many revisions are listed above the diff.
+const historyListRows = 8
+
+func NewHistory(tab models.Tab, revisions []models.Revision) HistoryModel {
+	return HistoryModel{tab: tab, revisions: revisions, base: -1}
+}
+
+func (m *HistoryModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m HistoryModel) Update(msg tea.Msg) (HistoryModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+			m.scroll = 0
+		}
+	case "j", "down":
+		if m.cursor < len(m.revisions)-1 {
+			m.cursor++
+			m.scroll = 0
+		}
+	case "home":
+		m.cursor = 0
+		m.scroll = 0
+	case "end":
+		m.cursor = len(m.revisions) - 1
+		m.scroll = 0
+	case "K", "pgup":
+		m.scroll -= 5
+		if m.scroll < 0 {
+			m.scroll = 0
+		}
+	case "J", "pgdown":
+		m.scroll += 5
+	case "b":
+		if m.base == m.cursor {
+			m.base = -1
+		} else {
+			m.base = m.cursor
+		}
+		m.scroll = 0
+	case "enter", "r":
+		if m.cursor < len(m.revisions) {
+			rev := m.revisions[m.cursor]
+			return m, func() tea.Msg {
+				return RestoreRevisionMsg{Revision: rev}
+			}
+		}
+	case "esc", "q":
+		return m, func() tea.Msg {
+			return HistoryClosedMsg{}
+		}
+	}
+	return m, nil
+}
+
+// revisionNumber numbers revisions from 1 for the oldest.
+func (m HistoryModel) revisionNumber(i int) int {
+	return len(m.revisions) - i
+}
+
+// compared returns the index of the revision the cursor is diffed against,
+// or -1 if there is none.
+func (m HistoryModel) compared() int {
+	if m.base >= 0 && m.base != m.cursor {
+		return m.base
+	}
+	if m.cursor+1 < len(m.revisions) {
+		return m.cursor + 1
+	}
+	return -1
+}
+
+func (m HistoryModel) View() string {
+	if len(m.revisions) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")).
+			Render("No revisions yet. Every save of " + m.tab.Name + " will be kept here.")
+	}
+
+	list := m.listLines()
+	diff := m.diffLines()
+
+	room := m.height - len(list) - 1
+	if room < 1 {
+		room = 1
+	}
+	scroll := m.scroll
+	if scroll > len(diff)-room {
+		scroll = len(diff) - room
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	end := scroll + room
+	if end > len(diff) {
+		end = len(diff)
+	}
+
+	rule := lipgloss.NewStyle().Foreground(lipgloss.Color("8")).
+		Render(strings.Repeat("─", max(m.width, 20)))
+	return strings.Join(append(append(list, rule), diff[scroll:end]...), "\n")
+}
+
+func (m HistoryModel) listLines() []string {
+	first := 0
+	if m.cursor >= historyListRows {
+		first = m.cursor - historyListRows + 1
+	}
+	last := first + historyListRows
+	if last > len(m.revisions) {
+		last = len(m.revisions)
+	}
+
+	var lines []string
+	for i := first; i < last; i++ {
+		rev := m.revisions[i]
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			style = style.Background(lipgloss.Color("12")).Foreground(lipgloss.Color("15"))
+		}
+
+		marker := "  "
+		if i == m.base {
+			marker = "◆ "
+		}
+		note := ""
+		if i == 0 {
+			note = "  (current)"
+		}
+		changes := "first revision"
+		if i+1 < len(m.revisions) {
+			changed := len(diffCells(&m.revisions[i+1].Tab, &rev.Tab))
+			changes = fmt.Sprintf("%d %s changed", changed, models.Plural(changed, "cell"))
+		}
+
+		lines = append(lines, style.Render(fmt.Sprintf("%s#%-4d %s  %-24s %s%s",
+			marker, m.revisionNumber(i), rev.CreatedAt.Format("2006-01-02 15:04:05"),
+			truncate(rev.Tab.Name, 24), changes, note)))
+	}
+	return lines
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// diffCells returns the cells whose characters differ between two versions
+// of a tab. Columns past the end of a shorter line count as blank.
+func diffCells(before, after *models.Tab) map[models.Position]bool {
+	changed := make(map[models.Position]bool)
+	for str := range after.Content {
+		a, b := []rune(before.Content[str]), []rune(after.Content[str])
+		for pos := 0; pos < len(a) || pos < len(b); pos++ {
+			if cellAt(a, pos) != cellAt(b, pos) {
+				changed[models.Position{String: str, Position: pos}] = true
+			}
+		}
+	}
+	return changed
+}
+
+func cellAt(line []rune, pos int) rune {
+	if pos < len(line) {
+		return line[pos]
+	}
+	return ' '
+}
+
+// diffLines renders the comparison: changed metadata first, then every
+// system with changes, where each changed string shows its old line (-)
+// above its new one (+) so changed columns line up.
+func (m HistoryModel) diffLines() []string {
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	newRev := m.revisions[m.cursor]
+	other := m.compared()
+	if other < 0 {
+		return []string{label.Render(fmt.Sprintf("#%d is the first revision of this tab.", m.revisionNumber(m.cursor)))}
+	}
+	oldRev := m.revisions[other]
+	before, after := &oldRev.Tab, &newRev.Tab
+
+	lines := []string{
+		label.Render(fmt.Sprintf("Changes from #%d (%s) to #%d (%s)",
+			m.revisionNumber(other), oldRev.CreatedAt.Format("2006-01-02 15:04"),
+			m.revisionNumber(m.cursor), newRev.CreatedAt.Format("2006-01-02 15:04"))),
+		"",
+	}
+
+	fields := []struct{ name, before, after string }{
+		{"Name", before.Name, after.Name},
+		{"Artist", before.Artist, after.Artist},
+		{"Tuning", before.TuningString(), after.TuningString()},
+		{"Tempo", fmt.Sprintf("%d bpm", before.Tempo), fmt.Sprintf("%d bpm", after.Tempo)},
+		{"Time", before.TimeSignature, after.TimeSignature},
+		{"Notes", firstLine(before.Notes), firstLine(after.Notes)},
+	}
+	for _, f := range fields {
+		if f.before != f.after || (f.name == "Notes" && before.Notes != after.Notes) {
+			lines = append(lines, label.Render(fmt.Sprintf("%-8s", f.name))+
+				removedStyle.Render(f.before)+" → "+addedStyle.Render(f.after))
+		}
+	}
+
+	changed := diffCells(before, after)
+	if len(changed) == 0 {
+		return append(lines, label.Render("The tab lines are unchanged."))
+	}
+	if len(lines) > 2 {
+		lines = append(lines, "")
+	}
+
+	// Break at the bars of whichever version is longer; columns are the
+	// same in both.
+	longer := after
+	if before.Length() > after.Length() {
+		longer = before
+	}
+	labels := after.TuningLabels()
+	width := m.width - len([]rune(labels[0])) - 4
+	if width < 16 {
+		width = 16
+	}
+
+	measures := longer.Measures()
+	for _, system := range longer.Systems(width) {
+		touched := false
+		for cell := range changed {
+			if cell.Position >= system.Start && cell.Position < system.End {
+				touched = true
+				break
+			}
+		}
+		if !touched {
+			continue
+		}
+
+		first := 1
+		for i, span := range measures {
+			if span.Start == system.Start {
+				first = i + 1
+			}
+		}
+		lines = append(lines, label.Render(fmt.Sprintf("Bar %d, columns %d-%d", first, system.Start+1, system.End)))
+
+		for str := range after.Content {
+			oldCells := systemCells(before.Content[str], system)
+			newCells := systemCells(after.Content[str], system)
+			if oldCells == newCells {
+				lines = append(lines, "  "+labels[str]+"|"+oldCells)
+				continue
+			}
+			lines = append(lines,
+				removedStyle.Render("- "+labels[str]+"|")+diffLine(oldCells, str, system, changed, removedStyle),
+				addedStyle.Render("+ "+labels[str]+"|")+diffLine(newCells, str, system, changed, addedStyle))
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+var (
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+)
+
+// systemCells returns the span of a line, padded with blanks past its end.
+func systemCells(line string, span models.Span) string {
+	runes := []rune(line)
+	cells := make([]rune, 0, span.Len())
+	for pos := span.Start; pos < span.End; pos++ {
+		cells = append(cells, cellAt(runes, pos))
+	}
+	return string(cells)
+}
+
+func diffLine(cells string, str int, span models.Span, changed map[models.Position]bool, style lipgloss.Style) string {
+	var b strings.Builder
+	for i, r := range []rune(cells) {
+		if changed[models.Position{String: str, Position: span.Start + i}] {
+			b.WriteString(style.Bold(true).Reverse(true).Render(string(r)))
+		} else {
+			b.WriteString(string(r))
+		}
+	}
+	return b.String()
+}
+
+func firstLine(s string) string {
+	line, _, more := strings.Cut(s, "\n")
+	if more {
+		line += " …"
+	}
+	return line
+}
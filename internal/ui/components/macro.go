@@ -0,0 +1,97 @@
+// internal/ui/components/macro.go
+package components
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxMacroDepth bounds nested @<reg> replays so a macro that calls itself
+// cannot recurse forever.
+const maxMacroDepth = 8
+
+// Named keys that can appear in a recorded macro. Anything else is replayed
+// as plain runes.
+var macroKeyTypes = map[string]tea.KeyType{
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"esc":       tea.KeyEsc,
+	"enter":     tea.KeyEnter,
+	"tab":       tea.KeyTab,
+	"backspace": tea.KeyBackspace,
+	"delete":    tea.KeyDelete,
+	"ctrl+h":    tea.KeyCtrlH,
+	" ":         tea.KeySpace,
+}
+
+func keyMsgFromString(s string) tea.KeyMsg {
+	if t, ok := macroKeyTypes[s]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func isMacroRegister(s string) bool {
+	return len(s) == 1 && s[0] >= 'a' && s[0] <= 'z'
+}
+
+// SetMacros shares the application's macro registers with the editor.
+// Registers recorded in this editor are written back into the same map.
+func (m *TabEditorModel) SetMacros(macros map[string][]string) {
+	m.macros = macros
+}
+
+// Recording returns the register currently being recorded, or "" if idle.
+func (m TabEditorModel) Recording() string {
+	return m.recording
+}
+
+// TakeRecordedMacro returns a macro whose recording finished since the last
+// call, so the caller can persist it.
+func (m *TabEditorModel) TakeRecordedMacro() (register string, keys []string, ok bool) {
+	if m.finished == "" {
+		return "", nil, false
+	}
+	register = m.finished
+	m.finished = ""
+	return register, m.macros[register], true
+}
+
+func (m *TabEditorModel) startRecording(register string) {
+	m.recording = register
+	m.recorded = nil
+}
+
+func (m *TabEditorModel) stopRecording() {
+	if m.macros == nil {
+		m.macros = make(map[string][]string)
+	}
+	m.macros[m.recording] = m.recorded
+	m.finished = m.recording
+	m.recording = ""
+	m.recorded = nil
+}
+
+func (m TabEditorModel) replayMacro(register string, count int) TabEditorModel {
+	if register == "@" {
+		register = m.lastMacro
+	}
+	keys, ok := m.macros[register]
+	if !ok || m.replayDepth >= maxMacroDepth {
+		return m
+	}
+	m.lastMacro = register
+
+	m.replayDepth++
+	for i := 0; i < count; i++ {
+		for _, k := range keys {
+			m, _ = m.Update(keyMsgFromString(k))
+		}
+	}
+	m.replayDepth--
+
+	return m
+}
@@ -0,0 +1,62 @@
+// internal/ui/components/preview.go
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// RenderPreview renders the opening measures of tab with the editor's
+// renderer, stacked as systems that fit in width, under a metadata block.
+// Output stops before height lines.
+func RenderPreview(tab *models.Tab, width, height int) string {
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	measures := tab.Measures()
+	duration := tab.Duration().Round(time.Second)
+
+	name := tab.Name
+	if tab.Favorite {
+		name = "★ " + name
+	}
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")).Render(name),
+	}
+	if tab.Artist != "" {
+		lines = append(lines, tab.Artist)
+	}
+	lines = append(lines,
+		"",
+		label.Render("Tuning  ")+tab.TuningString(),
+		label.Render("Tempo   ")+fmt.Sprintf("%d bpm", tab.Tempo),
+		label.Render("Time    ")+tab.TimeSignature,
+		label.Render("Length  ")+fmt.Sprintf("%d %s, %d:%02d",
+			len(measures), models.Plural(len(measures), "measure"), int(duration.Minutes()), int(duration.Seconds())%60),
+	)
+	if len(tab.Tags) > 0 {
+		lines = append(lines, label.Render("Tags    ")+strings.Join(tab.Tags, ", "))
+	}
+	lines = append(lines, "")
+
+	// Reuse the editor's renderer on a copy, without a cursor
+	preview := *tab
+	editor := NewTabEditor(&preview)
+	editor.preview = true
+	editor.wrap = true
+
+	for _, system := range preview.Systems(width - 3) {
+		if len(lines)+len(preview.Content) > height {
+			break
+		}
+		lines = append(lines, editor.renderSystem(system, nil)...)
+		lines = append(lines, "")
+	}
+
+	if len(lines) > height && height > 0 {
+		lines = lines[:height]
+	}
+	return strings.Join(lines, "\n")
+}
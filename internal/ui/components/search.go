@@ -0,0 +1,329 @@
+// internal/ui/components/search.go
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// fretPattern is a parsed editor search query.
+//
+//	7            fret 7 on any string
+//	7-9-7        consecutive notes 7, 9, 7 on one string (rests between are ignored)
+//	G:7-9-7      the same, only on the G string (labels e B G D A E, or 1-6)
+//	x32010       chord shape written low E to high e; x means no note
+//	x,10,12,12,11,x
+type fretPattern struct {
+	chord bool
+	str   int   // string to search for sequences, -1 for any
+	frets []int // sequence frets, or per-string chord frets indexed like Tab.Content (-1 = muted)
+}
+
+type searchMatch struct {
+	pos   models.Position // first note of the match
+	cells []models.Position
+}
+
+func parseFretPattern(query string) (fretPattern, error) {
+	p := fretPattern{str: -1}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return p, fmt.Errorf("empty pattern")
+	}
+
+	if label, rest, ok := strings.Cut(query, ":"); ok {
+		str, ok := models.ParseStringLabel(strings.TrimSpace(label))
+		if !ok {
+			return p, fmt.Errorf("unknown string %q", label)
+		}
+		p.str = str
+		query = strings.TrimSpace(rest)
+	}
+
+	if p.str < 0 && isChordShape(query) {
+		return parseChordShape(query)
+	}
+
+	for _, field := range strings.FieldsFunc(query, func(r rune) bool { return r == '-' || r == ' ' }) {
+		fret, err := strconv.Atoi(field)
+		if err != nil || fret < 0 || fret > models.MaxFret {
+			return p, fmt.Errorf("invalid fret %q", field)
+		}
+		p.frets = append(p.frets, fret)
+	}
+	if len(p.frets) == 0 {
+		return p, fmt.Errorf("empty pattern")
+	}
+
+	return p, nil
+}
+
+func isChordShape(query string) bool {
+	if strings.Contains(query, ",") {
+		return true
+	}
+	if len(query) != 6 {
+		return false
+	}
+	for _, r := range query {
+		if r != 'x' && r != 'X' && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func parseChordShape(query string) (fretPattern, error) {
+	p := fretPattern{chord: true, str: -1, frets: make([]int, 6)}
+
+	var fields []string
+	if strings.Contains(query, ",") {
+		fields = strings.Split(query, ",")
+	} else {
+		fields = strings.Split(query, "")
+	}
+	if len(fields) != 6 {
+		return p, fmt.Errorf("chord shape needs 6 strings, got %d", len(fields))
+	}
+
+	played := false
+	for i, field := range fields {
+		// Chord shapes are written low E first; Content is high e first
+		str := 5 - i
+		field = strings.TrimSpace(field)
+		if field == "x" || field == "X" {
+			p.frets[str] = -1
+			continue
+		}
+		fret, err := strconv.Atoi(field)
+		if err != nil || fret < 0 || fret > models.MaxFret {
+			return p, fmt.Errorf("invalid fret %q", field)
+		}
+		p.frets[str] = fret
+		played = true
+	}
+	if !played {
+		return p, fmt.Errorf("chord shape has no fretted strings")
+	}
+
+	return p, nil
+}
+
+// findMatches returns every match of p in tab ordered by column, then string.
+func findMatches(tab *models.Tab, p fretPattern) []searchMatch {
+	var matches []searchMatch
+
+	if p.chord {
+		matches = findChordMatches(tab, p)
+	} else {
+		for str, line := range tab.Content {
+			if p.str >= 0 && str != p.str {
+				continue
+			}
+			notes := models.ParseNotes(line, str)
+			for i := 0; i+len(p.frets) <= len(notes); i++ {
+				if !sequenceMatches(notes[i:i+len(p.frets)], p.frets) {
+					continue
+				}
+				match := searchMatch{pos: models.Position{String: str, Position: notes[i].Position}}
+				for _, n := range notes[i : i+len(p.frets)] {
+					match.cells = append(match.cells, noteCells(n)...)
+				}
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].pos.Position != matches[j].pos.Position {
+			return matches[i].pos.Position < matches[j].pos.Position
+		}
+		return matches[i].pos.String < matches[j].pos.String
+	})
+	return matches
+}
+
+func sequenceMatches(notes []models.Note, frets []int) bool {
+	for i, n := range notes {
+		if n.Fret != frets[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func findChordMatches(tab *models.Tab, p fretPattern) []searchMatch {
+	var byString [6]map[int]models.Note
+	maxLen := 0
+	for str, line := range tab.Content {
+		byString[str] = make(map[int]models.Note)
+		for _, n := range models.ParseNotes(line, str) {
+			byString[str][n.Position] = n
+		}
+		if len(line) > maxLen {
+			maxLen = len(line)
+		}
+	}
+
+	var matches []searchMatch
+	for pos := 0; pos < maxLen; pos++ {
+		match := searchMatch{pos: models.Position{String: -1, Position: pos}}
+		ok := true
+		for str, want := range p.frets {
+			n, played := byString[str][pos]
+			if want < 0 {
+				ok = !played
+			} else {
+				ok = played && n.Fret == want
+			}
+			if !ok {
+				break
+			}
+			if played {
+				if match.pos.String < 0 {
+					match.pos.String = str
+				}
+				match.cells = append(match.cells, noteCells(n)...)
+			}
+		}
+		if ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+func noteCells(n models.Note) []models.Position {
+	cells := make([]models.Position, 0, n.Width)
+	for i := 0; i < n.Width; i++ {
+		cells = append(cells, models.Position{String: n.String, Position: n.Position + i})
+	}
+	return cells
+}
+
+// Searching reports whether the editor is reading a search query, in which
+// case it wants every key.
+func (m TabEditorModel) Searching() bool {
+	return m.searching
+}
+
+func (m *TabEditorModel) startSearch(forward bool) {
+	m.searching = true
+	m.searchForward = forward
+	m.searchInput = ""
+	m.searchMsg = ""
+}
+
+func (m *TabEditorModel) updateSearchInput(key string) {
+	switch key {
+	case "esc":
+		m.searching = false
+	case "enter":
+		m.searching = false
+		if m.searchInput == "" {
+			// An empty query repeats the previous search
+			m.searchNext(false)
+			return
+		}
+		p, err := parseFretPattern(m.searchInput)
+		if err != nil {
+			m.searchMsg = "Invalid pattern: " + err.Error()
+			return
+		}
+		m.searchPattern = &p
+		m.searchQuery = m.searchInput
+		m.searchNext(false)
+	case "backspace", "ctrl+h":
+		if m.searchInput == "" {
+			m.searching = false
+			return
+		}
+		r := []rune(m.searchInput)
+		m.searchInput = string(r[:len(r)-1])
+	default:
+		if len([]rune(key)) == 1 {
+			m.searchInput += key
+		}
+	}
+}
+
+// searchNext moves the cursor to the next match in the search direction, or
+// the opposite one if reverse is set, wrapping around the ends of the tab.
+func (m *TabEditorModel) searchNext(reverse bool) {
+	if m.searchPattern == nil {
+		m.searchMsg = "No previous search"
+		return
+	}
+
+	matches := findMatches(m.tab, *m.searchPattern)
+	if len(matches) == 0 {
+		m.searchMsg = "Pattern not found: " + m.searchQuery
+		return
+	}
+
+	forward := m.searchForward != reverse
+	after := func(p models.Position) bool {
+		if p.Position != m.cursor.Position {
+			return p.Position > m.cursor.Position
+		}
+		return p.String > m.cursor.String
+	}
+
+	idx := -1
+	if forward {
+		for i, match := range matches {
+			if after(match.pos) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = 0
+		}
+	} else {
+		for i := len(matches) - 1; i >= 0; i-- {
+			if !after(matches[i].pos) && matches[i].pos != m.cursor {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = len(matches) - 1
+		}
+	}
+
+	m.cursor = matches[idx].pos
+	m.searchMsg = fmt.Sprintf("%s [%d/%d]", m.searchQuery, idx+1, len(matches))
+}
+
+// searchCells returns the cells of every current match, marking those of the
+// match under the cursor as current.
+func (m TabEditorModel) searchCells() map[models.Position]bool {
+	if m.searchPattern == nil {
+		return nil
+	}
+
+	cells := make(map[models.Position]bool)
+	for _, match := range findMatches(m.tab, *m.searchPattern) {
+		current := match.pos == m.cursor
+		for _, c := range match.cells {
+			cells[c] = cells[c] || current
+		}
+	}
+	return cells
+}
+
+func (m TabEditorModel) searchLine() string {
+	if m.searching {
+		prefix := "?"
+		if m.searchForward {
+			prefix = "/"
+		}
+		return prefix + m.searchInput + "█"
+	}
+	return m.searchMsg
+}
@@ -0,0 +1,512 @@
+// internal/ui/components/setlist.go
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// SetlistChangedMsg asks the parent to save a new or edited setlist.
+type SetlistChangedMsg struct {
+	Setlist models.Setlist
+}
+
+// SetlistActionMsg asks the parent to delete, export, play or add tabs to
+// a setlist. From is the entry to start playing at.
+type SetlistActionMsg struct {
+	Action  string // "delete", "export", "play", "stop" or "add"
+	Setlist models.Setlist
+	From    int
+}
+
+// SetlistClosedMsg is emitted when the user leaves the setlist view.
+type SetlistClosedMsg struct{}
+
+// SetlistModel lists the setlists beside the entries of the selected one.
+// Every edit is handed to the parent as a SetlistChangedMsg straight away.
+type SetlistModel struct {
+	setlists []models.Setlist
+	tabs     map[int]models.Tab
+	selected int
+	entry    int
+	entries  bool // focus is on the entries pane
+
+	editing string // "new", "rename" or "notes" while reading text input
+	input   string
+	pending string // first key of dd
+
+	playingID   int // setlist being played, 0 if none
+	playingSong int
+	width       int
+	height      int
+}
+
+// setlistPaneWidth is the width of the list of setlists.
+const setlistPaneWidth = 30
+
+func NewSetlistView(setlists []models.Setlist, tabs []models.Tab) SetlistModel {
+	m := SetlistModel{}
+	m.SetSetlists(setlists)
+	m.SetTabs(tabs)
+	return m
+}
+
+func (m *SetlistModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetSetlists replaces the setlists, keeping the selection on the same
+// setlist when it still exists.
+func (m *SetlistModel) SetSetlists(setlists []models.Setlist) {
+	var keepID int
+	if current := m.current(); current != nil {
+		keepID = current.ID
+	}
+
+	m.setlists = setlists
+	for i, setlist := range setlists {
+		if setlist.ID == keepID {
+			m.selected = i
+		}
+	}
+	m.clamp()
+}
+
+// SetTabs gives the view the library, to show entry names and lengths.
+func (m *SetlistModel) SetTabs(tabs []models.Tab) {
+	m.tabs = make(map[int]models.Tab, len(tabs))
+	for _, tab := range tabs {
+		m.tabs[tab.ID] = tab
+	}
+}
+
+// Select moves the selection to the setlist with the given ID.
+func (m *SetlistModel) Select(id int) {
+	for i, setlist := range m.setlists {
+		if setlist.ID == id {
+			m.selected = i
+			m.entry = 0
+		}
+	}
+}
+
+// SetPlaying marks which setlist and song the player is on; id 0 means
+// nothing is playing.
+func (m *SetlistModel) SetPlaying(id, song int) {
+	m.playingID = id
+	m.playingSong = song
+}
+
+// PlayingID returns the setlist being played, or 0.
+func (m SetlistModel) PlayingID() int {
+	return m.playingID
+}
+
+func (m *SetlistModel) clamp() {
+	if m.selected >= len(m.setlists) {
+		m.selected = len(m.setlists) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	count := 0
+	if current := m.current(); current != nil {
+		count = len(current.Entries)
+	}
+	if m.entry >= count {
+		m.entry = count - 1
+	}
+	if m.entry < 0 {
+		m.entry = 0
+	}
+	if count == 0 {
+		m.entries = false
+	}
+}
+
+func (m SetlistModel) current() *models.Setlist {
+	if m.selected < 0 || m.selected >= len(m.setlists) {
+		return nil
+	}
+	return &m.setlists[m.selected]
+}
+
+// Editing reports whether the view is reading text input.
+func (m SetlistModel) Editing() bool {
+	return m.editing != ""
+}
+
+func (m SetlistModel) Update(msg tea.Msg) (SetlistModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.editing != "" {
+		return m.updateInput(keyMsg)
+	}
+
+	key := keyMsg.String()
+	pending := m.pending
+	m.pending = ""
+
+	switch key {
+	case "esc", "q":
+		if m.entries && key == "esc" {
+			m.entries = false
+			return m, nil
+		}
+		return m, func() tea.Msg { return SetlistClosedMsg{} }
+	case "n":
+		if !m.entries {
+			m.editing, m.input = "new", ""
+			return m, nil
+		}
+	}
+
+	current := m.current()
+	if current == nil {
+		return m, nil
+	}
+
+	switch key {
+	case "d":
+		if pending != "d" {
+			m.pending = "d"
+			return m, nil
+		}
+		if !m.entries {
+			return m, m.action("delete", 0)
+		}
+		return m.edit(func(s *models.Setlist) {
+			s.Entries = append(s.Entries[:m.entry:m.entry], s.Entries[m.entry+1:]...)
+		})
+	case "x":
+		return m, m.action("export", 0)
+	case "p":
+		from := 0
+		if m.entries {
+			from = m.entry
+		}
+		return m, m.action("play", from)
+	case "s":
+		return m, m.action("stop", 0)
+	case "a":
+		return m, m.action("add", 0)
+	case "g", "G":
+		step := time.Second
+		if key == "g" {
+			step = -time.Second
+		}
+		return m.edit(func(s *models.Setlist) {
+			s.Gap += step
+			if s.Gap < 0 {
+				s.Gap = 0
+			}
+		})
+	}
+
+	if !m.entries {
+		switch key {
+		case "k", "up":
+			if m.selected > 0 {
+				m.selected--
+				m.entry = 0
+			}
+		case "j", "down":
+			if m.selected < len(m.setlists)-1 {
+				m.selected++
+				m.entry = 0
+			}
+		case "r":
+			m.editing, m.input = "rename", current.Name
+		case "enter", "l", "right":
+			if len(current.Entries) > 0 {
+				m.entries = true
+			}
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "k", "up":
+		if m.entry > 0 {
+			m.entry--
+		}
+	case "j", "down":
+		if m.entry < len(current.Entries)-1 {
+			m.entry++
+		}
+	case "h", "left":
+		m.entries = false
+	case "K", "J":
+		to := m.entry + 1
+		if key == "K" {
+			to = m.entry - 1
+		}
+		if to < 0 || to >= len(current.Entries) {
+			return m, nil
+		}
+		from := m.entry
+		m.entry = to
+		return m.edit(func(s *models.Setlist) {
+			s.Entries[from], s.Entries[to] = s.Entries[to], s.Entries[from]
+		})
+	case "c", "C":
+		step := 1
+		if key == "C" {
+			step = -1
+		}
+		return m.editEntry(func(e *models.SetlistEntry, tab models.Tab) {
+			e.Capo = clampInt(e.Capo+step, 0, 12)
+		})
+	case "+", "=", "-":
+		step := 1
+		if key == "-" {
+			step = -1
+		}
+		return m.editEntry(func(e *models.SetlistEntry, tab models.Tab) {
+			e.Transpose = clampInt(e.Transpose+step, -12, 12)
+		})
+	case "<", ">":
+		step := 5
+		if key == "<" {
+			step = -5
+		}
+		return m.editEntry(func(e *models.SetlistEntry, tab models.Tab) {
+			e.Tempo = clampInt(e.TempoFor(&tab)+step, 20, 300)
+			if e.Tempo == tab.Tempo {
+				e.Tempo = 0
+			}
+		})
+	case "R":
+		return m.editEntry(func(e *models.SetlistEntry, tab models.Tab) {
+			e.Capo, e.Transpose, e.Tempo = 0, 0, 0
+		})
+	case "e", "enter":
+		m.editing, m.input = "notes", current.Entries[m.entry].Notes
+	}
+	return m, nil
+}
+
+func (m SetlistModel) updateInput(msg tea.KeyMsg) (SetlistModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.editing = ""
+	case tea.KeyEnter:
+		editing, input := m.editing, strings.TrimSpace(m.input)
+		m.editing = ""
+		switch editing {
+		case "new":
+			if input == "" {
+				return m, nil
+			}
+			setlist := *models.NewSetlist(input)
+			return m, func() tea.Msg { return SetlistChangedMsg{Setlist: setlist} }
+		case "rename":
+			if input == "" {
+				return m, nil
+			}
+			return m.edit(func(s *models.Setlist) { s.Name = input })
+		case "notes":
+			return m.editEntry(func(e *models.SetlistEntry, tab models.Tab) { e.Notes = input })
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// edit applies change to a copy of the selected setlist and hands it to
+// the parent to save. The view shows the change at once.
+func (m SetlistModel) edit(change func(s *models.Setlist)) (SetlistModel, tea.Cmd) {
+	current := m.current()
+	if current == nil {
+		return m, nil
+	}
+
+	setlist := *current
+	setlist.Entries = append([]models.SetlistEntry(nil), current.Entries...)
+	change(&setlist)
+
+	m.setlists = append([]models.Setlist(nil), m.setlists...)
+	m.setlists[m.selected] = setlist
+	m.clamp()
+	return m, func() tea.Msg { return SetlistChangedMsg{Setlist: setlist} }
+}
+
+func (m SetlistModel) editEntry(change func(e *models.SetlistEntry, tab models.Tab)) (SetlistModel, tea.Cmd) {
+	index := m.entry
+	return m.edit(func(s *models.Setlist) {
+		if index < len(s.Entries) {
+			change(&s.Entries[index], m.tabs[s.Entries[index].TabID])
+		}
+	})
+}
+
+func (m SetlistModel) action(action string, from int) tea.Cmd {
+	setlist := *m.current()
+	return func() tea.Msg {
+		return SetlistActionMsg{Action: action, Setlist: setlist, From: from}
+	}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Length returns how long the setlist plays, gaps included. Entries whose
+// tab is missing count as nothing.
+func (m SetlistModel) Length(setlist *models.Setlist) time.Duration {
+	var total time.Duration
+	played := 0
+	for _, entry := range setlist.Entries {
+		tab, ok := m.tabs[entry.TabID]
+		if !ok {
+			continue
+		}
+		tab.Tempo = entry.TempoFor(&tab)
+		total += tab.Duration()
+		played++
+	}
+	if played > 1 {
+		total += time.Duration(played-1) * setlist.Gap
+	}
+	return total
+}
+
+func formatLength(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func (m SetlistModel) View() string {
+	left := m.listPane()
+	right := m.entriesPane()
+
+	view := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(setlistPaneWidth).Render(left),
+		lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("8")).
+			PaddingLeft(1).
+			Render(right))
+
+	if m.editing != "" {
+		prompts := map[string]string{"new": "New setlist: ", "rename": "Rename setlist: ", "notes": "Notes: "}
+		view = lipgloss.JoinVertical(lipgloss.Left, view, "",
+			lipgloss.NewStyle().Foreground(lipgloss.Color("13")).Render(prompts[m.editing]+m.input+"█"))
+	}
+	return view
+}
+
+func (m SetlistModel) listPane() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	if len(m.setlists) == 0 {
+		return dim.Render("No setlists yet.\nPress n to create one.")
+	}
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("8")).Render("Setlists")}
+	for i, setlist := range m.setlists {
+		style := lipgloss.NewStyle()
+		if i == m.selected {
+			if m.entries {
+				style = style.Bold(true)
+			} else {
+				style = style.Background(lipgloss.Color("12")).Foreground(lipgloss.Color("15"))
+			}
+		}
+
+		marker := "  "
+		if setlist.ID == m.playingID {
+			marker = "▶ "
+		}
+		info := fmt.Sprintf(" %d, %s", len(setlist.Entries), formatLength(m.Length(&setlist)))
+		name := truncate(setlist.Name, setlistPaneWidth-len(marker)-len(info)-1)
+		pad := setlistPaneWidth - 1 - len([]rune(marker+name+info))
+		lines = append(lines, style.Render(marker+name+strings.Repeat(" ", max(pad, 0))+info))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m SetlistModel) entriesPane() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	setlist := m.current()
+	if setlist == nil {
+		return ""
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")).Render(setlist.Name) +
+			dim.Render(fmt.Sprintf("  %d %s, %s with %s gaps",
+				len(setlist.Entries), models.Plural(len(setlist.Entries), "song"),
+				formatLength(m.Length(setlist)), setlist.Gap)),
+		"",
+	}
+	if len(setlist.Entries) == 0 {
+		return strings.Join(append(lines, dim.Render("Empty. Press a, mark tabs in the browser, then press A.")), "\n")
+	}
+
+	nameWidth := m.width - setlistPaneWidth - 40
+	if nameWidth < 12 {
+		nameWidth = 12
+	}
+	lines = append(lines, dim.Render(fmt.Sprintf("   %-3s %-*s %4s %6s %5s %6s", "#", nameWidth, "Song", "Capo", "Transp", "Tempo", "Length")))
+
+	for i, entry := range setlist.Entries {
+		style := lipgloss.NewStyle()
+		if m.entries && i == m.entry {
+			style = style.Background(lipgloss.Color("12")).Foreground(lipgloss.Color("15"))
+		}
+
+		marker := "  "
+		if setlist.ID == m.playingID && i == m.playingSong {
+			marker = "▶ "
+		}
+
+		tab, ok := m.tabs[entry.TabID]
+		if !ok {
+			lines = append(lines, style.Render(fmt.Sprintf("%s %-3d %-*s", marker, i+1, nameWidth,
+				truncate(fmt.Sprintf("(tab #%d is in the trash)", entry.TabID), nameWidth))))
+			continue
+		}
+
+		capo, transpose := "", ""
+		if entry.Capo > 0 {
+			capo = fmt.Sprintf("%d", entry.Capo)
+		}
+		if entry.Transpose != 0 {
+			transpose = fmt.Sprintf("%+d", entry.Transpose)
+		}
+		tempo := entry.TempoFor(&tab)
+		played := tab
+		played.Tempo = tempo
+		tempoText := fmt.Sprintf("%d", tempo)
+		if entry.Tempo > 0 {
+			tempoText += "*"
+		}
+
+		lines = append(lines, style.Render(fmt.Sprintf("%s %-3d %-*s %4s %6s %5s %6s",
+			marker, i+1, nameWidth, truncate(tab.Name, nameWidth), capo, transpose, tempoText,
+			formatLength(played.Duration()))))
+		if entry.Notes != "" {
+			lines = append(lines, dim.Render("       "+truncate(entry.Notes, nameWidth+20)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -3,6 +3,7 @@ package components
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -11,6 +12,55 @@ import (
 	"github.com/Cod-e-Codes/tuitar/internal/models"
 )
 
+// LargeLibrary is the number of tabs above which the browser filter ranks
+// only Storage.SearchTabs results instead of the whole library in memory.
+const LargeLibrary = 500
+
+// FilterChangedMsg is emitted when the filter text changes. The parent
+// answers with SearchResultsMsg, whose full-text matches (lyrics, riffs) are
+// listed after the fuzzy metadata matches. Query leaves out #tag terms,
+// which the browser applies itself.
+type FilterChangedMsg struct {
+	Query string
+}
+
+// BrowserActionMsg asks the parent to delete, duplicate or rename tabs, or
+// in the trash to restore or purge them. Tabs holds the marked tabs, or the
+// one under the cursor if none are marked; rename always targets the tab
+// under the cursor.
+type BrowserActionMsg struct {
+	Action string // "delete", "duplicate", "rename", "tags", "favorite", "setlist", "export", "restore" or "purge"
+	Tabs   []models.Tab
+}
+
+// TrashToggledMsg is emitted when the browser switches between the library
+// and the trash. The parent answers with SetTabs for the list now shown.
+type TrashToggledMsg struct {
+	Trash bool
+}
+
+// SearchResultsMsg carries Storage.SearchTabs results for Query.
+type SearchResultsMsg struct {
+	Query string
+	Tabs  []models.Tab
+}
+
+// Searchable metadata fields, in display order
+const (
+	fieldName = iota
+	fieldArtist
+	fieldTuning
+	fieldTags
+	numFields
+)
+
+type browserItem struct {
+	tab      models.Tab
+	score    int
+	marks    [numFields][]int // matched rune positions per field
+	fullText bool             // matched only by SearchTabs, not by the fuzzy filter
+}
+
 type TabBrowserModel struct {
 	tabs     []models.Tab
 	cursor   int
@@ -18,15 +68,42 @@ type TabBrowserModel struct {
 	filter   string
 	width    int
 	height   int
+
+	filtering  bool          // reading filter input
+	items      []browserItem // tabs passing the filter, in display order
+	results    []models.Tab  // SearchTabs results for large libraries
+	resultsFor string
+
+	prefs     BrowserPrefs
+	rows      []browserRow
+	collapsed map[string]bool // collapsed group headers
+
+	showPreview bool
+
+	marked  map[int]bool // tab IDs selected for bulk operations
+	pending string       // first key of dd / yy
+
+	trash     bool // listing trashed tabs instead of the library
+	favorites bool // listing only favorite tabs
 }
 
+// previewMinWidth is the narrowest window that fits the list beside a
+// preview pane.
+const previewMinWidth = 100
+
 func NewTabBrowser(tabs []models.Tab) TabBrowserModel {
 	vp := viewport.New(80, 20)
-	
-	return TabBrowserModel{
-		tabs:     tabs,
-		viewport: vp,
+
+	m := TabBrowserModel{
+		tabs:        tabs,
+		viewport:    vp,
+		prefs:       DefaultBrowserPrefs(),
+		collapsed:   make(map[string]bool),
+		showPreview: true,
+		marked:      make(map[int]bool),
 	}
+	m.refresh()
+	return m
 }
 
 func (m *TabBrowserModel) SetSize(width, height int) {
@@ -38,70 +115,479 @@ func (m *TabBrowserModel) SetSize(width, height int) {
 
 func (m TabBrowserModel) Update(msg tea.Msg) (TabBrowserModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case SearchResultsMsg:
+		if _, text := splitFilter(m.filter); msg.Query == text {
+			m.results = msg.Tabs
+			m.resultsFor = msg.Query
+			m.refresh()
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+
+		pending := m.pending
+		m.pending = ""
+
 		switch msg.String() {
+		case "d", "y":
+			if pending != msg.String() {
+				m.pending = msg.String()
+				return m, nil
+			}
+			action := "delete"
+			switch {
+			case m.trash && pending == "y":
+				return m, nil
+			case m.trash:
+				action = "purge"
+			case pending == "y":
+				action = "duplicate"
+			}
+			return m, m.action(action, m.targets())
+		case "u":
+			if m.trash {
+				return m, m.action("restore", m.targets())
+			}
+			return m, nil
+		case "t":
+			m.trash = !m.trash
+			m.marked = make(map[int]bool)
+			m.filter = ""
+			m.results = nil
+			m.resultsFor = ""
+			m.cursor = 0
+			trash := m.trash
+			return m, func() tea.Msg {
+				return TrashToggledMsg{Trash: trash}
+			}
+		case "T", "f", "A", "x":
+			if m.trash {
+				return m, nil
+			}
+			action := "tags"
+			switch msg.String() {
+			case "f":
+				action = "favorite"
+			case "A":
+				action = "setlist"
+			case "x":
+				action = "export"
+			}
+			return m, m.action(action, m.targets())
+		case "F":
+			m.favorites = !m.favorites
+			m.refresh()
+			return m, nil
+		case "r":
+			if selected := m.Selected(); selected != nil && !m.trash {
+				return m, m.action("rename", []models.Tab{*selected})
+			}
+			return m, nil
+		case "v":
+			if selected := m.Selected(); selected != nil {
+				if m.marked[selected.ID] {
+					delete(m.marked, selected.ID)
+				} else {
+					m.marked[selected.ID] = true
+				}
+				if m.cursor < len(m.rows)-1 {
+					m.cursor++
+				}
+			}
+			return m, nil
+		case "V":
+			if len(m.marked) > 0 {
+				m.marked = make(map[int]bool)
+			} else {
+				for _, item := range m.items {
+					m.marked[item.tab.ID] = true
+				}
+			}
+			return m, nil
 		case "k", "up":
 			if m.cursor > 0 {
 				m.cursor--
 			}
 		case "j", "down":
-			if m.cursor < len(m.tabs)-1 {
+			if m.cursor < len(m.rows)-1 {
 				m.cursor++
 			}
 		case "home":
 			m.cursor = 0
 		case "end":
-			m.cursor = len(m.tabs) - 1
+			m.cursor = len(m.rows) - 1
+		case "s":
+			m.prefs.Sort = nextOf(SortColumns, m.prefs.Sort)
+			return m.prefsChanged()
+		case "S":
+			m.prefs.Desc = !m.prefs.Desc
+			return m.prefsChanged()
+		case "g":
+			m.prefs.Group = nextOf(GroupModes, m.prefs.Group)
+			return m.prefsChanged()
+		case "enter", "h", "left", "l", "right":
+			if m.cursor < len(m.rows) && m.rows[m.cursor].item < 0 {
+				group := m.rows[m.cursor].group
+				switch msg.String() {
+				case "enter":
+					m.collapsed[group] = !m.collapsed[group]
+				case "h", "left":
+					m.collapsed[group] = true
+				default:
+					delete(m.collapsed, group)
+				}
+				m.refresh()
+			}
+			return m, nil
+		case "p":
+			m.showPreview = !m.showPreview
+			return m, nil
+		case "/":
+			m.filtering = true
+			return m, nil
+		case "esc":
+			if m.filter != "" {
+				return m.setFilter("")
+			}
 		}
 	}
-	
+
 	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
 }
 
+func (m TabBrowserModel) updateFilter(msg tea.KeyMsg) (TabBrowserModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.filtering = false
+		return m.setFilter("")
+	case tea.KeyEnter:
+		m.filtering = false
+		return m, nil
+	case tea.KeyBackspace:
+		r := []rune(m.filter)
+		if len(r) == 0 {
+			m.filtering = false
+			return m, nil
+		}
+		return m.setFilter(string(r[:len(r)-1]))
+	case tea.KeyRunes, tea.KeySpace:
+		return m.setFilter(m.filter + string(msg.Runes))
+	}
+	return m, nil
+}
+
+func (m TabBrowserModel) setFilter(filter string) (TabBrowserModel, tea.Cmd) {
+	m.filter = filter
+	m.refresh()
+	m.cursor = 0
+
+	// Storage search only covers the library
+	_, text := splitFilter(filter)
+	if text == "" || m.trash {
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return FilterChangedMsg{Query: text}
+	}
+}
+
+// splitFilter separates #tag terms from the words of a filter.
+func splitFilter(filter string) (tags []string, text string) {
+	var words []string
+	for _, term := range strings.Fields(filter) {
+		if len(term) > 1 && term[0] == '#' {
+			tags = append(tags, term[1:])
+		} else {
+			words = append(words, term)
+		}
+	}
+	return tags, strings.Join(words, " ")
+}
+
+// keep reports whether tab passes the #tag terms and the favorites toggle,
+// which narrow the list before any ranking. A #tag term matches any tag it
+// starts, so the list narrows while the tag is typed.
+func (m *TabBrowserModel) keep(tab *models.Tab, tags []string) bool {
+	if m.favorites && !tab.Favorite {
+		return false
+	}
+	for _, prefix := range tags {
+		found := false
+		for _, tag := range tab.Tags {
+			if len(tag) >= len(prefix) && strings.EqualFold(tag[:len(prefix)], prefix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// applyFilter rebuilds the visible items. A #tag term keeps only tabs
+// tagged with it; every other word must fuzzy-match one of the fields, and items
+// are ranked by their summed score. SearchTabs results that the fuzzy
+// filter missed follow in their own order. Large libraries rank only the
+// SearchTabs results, falling back to the in-memory list until they arrive.
+func (m *TabBrowserModel) applyFilter() {
+	m.items = nil
+
+	tags, text := splitFilter(m.filter)
+	terms := strings.Fields(text)
+	haveResults := len(terms) > 0 && m.resultsFor == text
+	candidates := m.tabs
+	if haveResults && len(m.tabs) > LargeLibrary {
+		candidates = m.results
+	}
+
+	for _, tab := range candidates {
+		if !m.keep(&tab, tags) {
+			continue
+		}
+		item := browserItem{tab: tab}
+		fields := [numFields]string{tab.Name, tab.Artist, tab.TuningString(), strings.Join(tab.Tags, " ")}
+
+		matched := true
+		for _, term := range terms {
+			best, bestField := 0, -1
+			var bestMarks []int
+			for f, text := range fields {
+				if score, marks, ok := fuzzyMatch(term, text); ok && (bestField < 0 || score > best) {
+					best, bestField, bestMarks = score, f, marks
+				}
+			}
+			if bestField < 0 {
+				matched = false
+				break
+			}
+			item.score += best
+			item.marks[bestField] = append(item.marks[bestField], bestMarks...)
+		}
+
+		if matched {
+			m.items = append(m.items, item)
+		}
+	}
+
+	if len(terms) > 0 {
+		sort.SliceStable(m.items, func(i, j int) bool {
+			return m.items[i].score > m.items[j].score
+		})
+	}
+
+	if haveResults {
+		listed := make(map[int]bool, len(m.items))
+		for _, item := range m.items {
+			listed[item.tab.ID] = true
+		}
+		for _, tab := range m.results {
+			if !listed[tab.ID] && m.keep(&tab, tags) {
+				m.items = append(m.items, browserItem{tab: tab, fullText: true})
+			}
+		}
+	}
+}
+
 func (m TabBrowserModel) View() string {
 	if len(m.tabs) == 0 {
+		empty := "No tabs found. Press Ctrl+N to create a new tab."
+		if m.trash {
+			empty = "The trash is empty. Press t to return to the library."
+		}
 		return lipgloss.NewStyle().
 			Foreground(lipgloss.Color("8")).
-			Render("No tabs found. Press Ctrl+N to create a new tab.")
+			Render(empty)
 	}
-	
+
 	var items []string
-	
-	for i, tab := range m.tabs {
+
+	for i, row := range m.rows {
 		style := lipgloss.NewStyle()
-		
+
 		if i == m.cursor {
 			style = style.Background(lipgloss.Color("12")).Foreground(lipgloss.Color("15"))
 		}
-		
-		// Format: [ID] Name - Artist (Date)
-		item := fmt.Sprintf("[%d] %s", tab.ID, tab.Name)
-		if tab.Artist != "" {
-			item += fmt.Sprintf(" - %s", tab.Artist)
+
+		if row.item < 0 {
+			items = append(items, m.renderGroupHeader(row, style))
+		} else {
+			items = append(items, m.renderItem(m.items[row.item], style))
+		}
+	}
+
+	if len(m.items) == 0 {
+		empty := "No tabs match the filter. Press Esc to clear it."
+		if m.favorites && m.filter == "" {
+			empty = "No favorites yet. Press f to mark one, F to show all tabs."
 		}
-		item += fmt.Sprintf(" (%s)", tab.UpdatedAt.Format("2006-01-02"))
-		
-		items = append(items, style.Render(item))
+		items = append(items, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")).
+			Render(empty))
 	}
-	
+
 	content := strings.Join(items, "\n")
+	m.viewport.Width = m.listWidth()
 	m.viewport.SetContent(content)
-	
-	return m.viewport.View()
+
+	showFilter := m.filtering || m.filter != ""
+	m.viewport.Height-- // column headers
+	if showFilter {
+		m.viewport.Height--
+	}
+
+	// Keep the cursor row visible
+	if height := m.viewport.Height; m.cursor >= height && height > 0 {
+		m.viewport.SetYOffset(m.cursor - height + 1)
+	} else {
+		m.viewport.SetYOffset(0)
+	}
+
+	list := lipgloss.JoinVertical(lipgloss.Left, m.headerLine(), m.viewport.View())
+	if showFilter {
+		list = lipgloss.JoinVertical(lipgloss.Left, m.filterLine(), list)
+	}
+
+	if !m.previewVisible() {
+		return list
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, m.previewPane())
+}
+
+func (m TabBrowserModel) previewVisible() bool {
+	return m.showPreview && m.width >= previewMinWidth
+}
+
+// listWidth is the width left for the list beside the preview pane.
+func (m TabBrowserModel) listWidth() int {
+	if !m.previewVisible() {
+		return m.width
+	}
+	return m.width * 11 / 20
+}
+
+func (m TabBrowserModel) previewPane() string {
+	width := m.width - m.listWidth() - 4
+	style := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(lipgloss.Color("8")).
+		MarginLeft(1).
+		PaddingLeft(1).
+		Width(width + 1)
+
+	selected := m.Selected()
+	if selected == nil {
+		return style.Render(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("No tab selected"))
+	}
+	return style.Render(RenderPreview(selected, width, m.height))
+}
+
+func (m TabBrowserModel) filterLine() string {
+	prompt := "/" + m.filter
+	if m.filtering {
+		prompt += "█"
+	}
+	count := fmt.Sprintf("  %d/%d", len(m.items), len(m.tabs))
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("13")).Render(prompt) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(count)
+}
+
+// highlightMarks renders text with the runes at marks in markStyle.
+func highlightMarks(text string, marks []int, style, markStyle lipgloss.Style) string {
+	if len(marks) == 0 {
+		return style.Render(text)
+	}
+
+	marked := make(map[int]bool, len(marks))
+	for _, i := range marks {
+		marked[i] = true
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(text) {
+		if marked[i] {
+			b.WriteString(markStyle.Render(string(r)))
+		} else {
+			b.WriteString(style.Render(string(r)))
+		}
+	}
+	return b.String()
 }
 
 func (m TabBrowserModel) Cursor() int {
 	return m.cursor
 }
 
+// Selected returns the tab under the cursor, or nil if the cursor is on a
+// group header or nothing is listed.
+func (m TabBrowserModel) Selected() *models.Tab {
+	if m.cursor < 0 || m.cursor >= len(m.rows) || m.rows[m.cursor].item < 0 {
+		return nil
+	}
+	tab := m.items[m.rows[m.cursor].item].tab
+	return &tab
+}
+
+// targets returns the marked tabs in display order, or the tab under the
+// cursor when nothing is marked.
+func (m TabBrowserModel) targets() []models.Tab {
+	var tabs []models.Tab
+	for _, item := range m.items {
+		if m.marked[item.tab.ID] {
+			tabs = append(tabs, item.tab)
+		}
+	}
+	if len(tabs) == 0 {
+		if selected := m.Selected(); selected != nil {
+			tabs = append(tabs, *selected)
+		}
+	}
+	return tabs
+}
+
+func (m TabBrowserModel) action(action string, tabs []models.Tab) tea.Cmd {
+	if len(tabs) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return BrowserActionMsg{Action: action, Tabs: tabs}
+	}
+}
+
+// ClearMarks unmarks every tab, after a bulk operation has run.
+func (m *TabBrowserModel) ClearMarks() {
+	m.marked = make(map[int]bool)
+}
+
+// Trash reports whether the browser is listing the trash.
+func (m TabBrowserModel) Trash() bool {
+	return m.trash
+}
+
+// Filtering reports whether the browser is reading filter input, in which
+// case it wants every key.
+func (m TabBrowserModel) Filtering() bool {
+	return m.filtering
+}
+
 func (m *TabBrowserModel) SetTabs(tabs []models.Tab) {
 	m.tabs = tabs
-	if m.cursor >= len(tabs) {
-		m.cursor = len(tabs) - 1
+	m.refresh()
+
+	// Forget marks on tabs that no longer exist
+	present := make(map[int]bool, len(tabs))
+	for _, tab := range tabs {
+		present[tab.ID] = true
 	}
-	if m.cursor < 0 {
-		m.cursor = 0
+	for id := range m.marked {
+		if !present[id] {
+			delete(m.marked, id)
+		}
 	}
 }
@@ -23,6 +23,29 @@ type TabEditorModel struct {
 	changed         bool
 	editMode        models.EditMode
 	highlightedPos  []models.Position // For playback highlighting
+
+	// Vim-style counts and macros
+	count       int
+	pending     string // first key of a two-key command ("q" or "@")
+	recording   string // register being recorded
+	recorded    []string
+	finished    string // register whose recording just finished
+	macros      map[string][]string
+	lastMacro   string
+	replayDepth int
+
+	// Fret pattern search
+	searching     bool
+	searchForward bool
+	searchInput   string
+	searchQuery   string
+	searchPattern *fretPattern
+	searchMsg     string
+
+	// Scrolling and layout
+	xOffset int  // first visible column when not wrapping
+	wrap    bool // break the tab into stacked systems at measure boundaries
+	preview bool // read-only rendering without a cursor
 }
 
 func NewTabEditor(tab *models.Tab) TabEditorModel {
@@ -53,12 +76,12 @@ func (m *TabEditorModel) SetSize(width, height int) {
 	m.height = height
 	m.viewport.Width = width
 	m.viewport.Height = height - 4 // Reserve space for headers
+	m.scrollToCursor()
 }
 
-// Updated to set changed = true to force re-render on highlight change
+// Highlights only affect rendering; they do not mark the tab as changed
 func (m *TabEditorModel) SetHighlightedPositions(positions []models.Position) {
 	m.highlightedPos = positions
-	m.changed = true
 }
 
 // Update now handles external highlight update message to refresh highlights
@@ -66,72 +89,168 @@ func (m TabEditorModel) Update(msg tea.Msg) (TabEditorModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case HighlightUpdateMsg:
 		m.highlightedPos = msg.Positions
-		m.changed = true
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		// Navigation keys work in both modes
-		case "h", "left":
-			if m.cursor.Position > 0 {
-				m.cursor.Position--
+		m = m.updateKey(msg)
+		m.scrollToCursor()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m TabEditorModel) updateKey(msg tea.KeyMsg) TabEditorModel {
+	key := msg.String()
+
+	if m.recording != "" && m.replayDepth == 0 {
+		if key == "q" && m.pending == "" && !m.searching && m.editMode == models.EditNormal {
+			m.stopRecording()
+			return m
+		}
+		m.recorded = append(m.recorded, key)
+	}
+
+	if m.searching {
+		m.updateSearchInput(key)
+		return m
+	}
+
+	// Second key of a two-key command (q<reg>, @<reg>)
+	if m.pending != "" {
+		pending := m.pending
+		m.pending = ""
+		count := m.takeCount()
+		switch pending {
+		case "q":
+			if isMacroRegister(key) {
+				m.startRecording(key)
 			}
-		case "l", "right":
-			maxPos := len(m.tab.Content[m.cursor.String]) - 1
-			if m.cursor.Position < maxPos {
-				m.cursor.Position++
+		case "@":
+			if isMacroRegister(key) || key == "@" {
+				m = m.replayMacro(key, count)
 			}
-		case "k", "up":
-			if m.cursor.String > 0 {
-				m.cursor.String--
+		}
+		return m
+	}
+
+	if m.editMode == models.EditNormal {
+		switch key {
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			m.count = m.count*10 + int(key[0]-'0')
+			return m
+		case "0":
+			if m.count > 0 {
+				m.count *= 10
+				return m
 			}
-		case "j", "down":
-			if m.cursor.String < 5 {
-				m.cursor.String++
+		case "q", "@":
+			if m.recording == "" || key == "@" {
+				m.pending = key
+				return m
 			}
-		case "home":
-			m.cursor.Position = 0
-		case "end":
-			m.cursor.Position = len(m.tab.Content[m.cursor.String]) - 1
-
-		// Insert mode specific keys
-		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
-			if m.editMode == models.EditInsert {
-				m.insertCharAt(m.cursor, rune(msg.String()[0]))
-				m.changed = true
-				if m.cursor.Position < len(m.tab.Content[m.cursor.String])-1 {
-					m.cursor.Position++
-				}
+		case "/", "?":
+			m.count = 0
+			m.startSearch(key == "/")
+			return m
+		}
+	}
+
+	for n := m.takeCount(); n > 0; n-- {
+		m.handleKey(key)
+	}
+	return m
+}
+
+func (m *TabEditorModel) takeCount() int {
+	count := m.count
+	m.count = 0
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+func (m *TabEditorModel) handleKey(key string) {
+	switch key {
+	// Mode switching
+	case "i":
+		if m.editMode == models.EditNormal {
+			m.editMode = models.EditInsert
+		}
+	case "esc":
+		m.editMode = models.EditNormal
+
+	// Navigation keys work in both modes
+	case "h", "left":
+		if m.cursor.Position > 0 {
+			m.cursor.Position--
+		}
+	case "l", "right":
+		maxPos := len(m.tab.Content[m.cursor.String]) - 1
+		if m.cursor.Position < maxPos {
+			m.cursor.Position++
+		}
+	case "k", "up":
+		if m.cursor.String > 0 {
+			m.cursor.String--
+		}
+	case "j", "down":
+		if m.cursor.String < 5 {
+			m.cursor.String++
+		}
+	case "home":
+		m.cursor.Position = 0
+	case "end":
+		m.cursor.Position = len(m.tab.Content[m.cursor.String]) - 1
+
+	// Insert mode specific keys
+	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if m.editMode == models.EditInsert {
+			m.insertCharAt(m.cursor, rune(key[0]))
+			m.changed = true
+			if m.cursor.Position < len(m.tab.Content[m.cursor.String])-1 {
+				m.cursor.Position++
 			}
-		case "-":
-			if m.editMode == models.EditInsert {
-				m.insertCharAt(m.cursor, '-')
-				m.changed = true
-				if m.cursor.Position < len(m.tab.Content[m.cursor.String])-1 {
-					m.cursor.Position++
-				}
+		}
+	case "-":
+		if m.editMode == models.EditInsert {
+			m.insertCharAt(m.cursor, '-')
+			m.changed = true
+			if m.cursor.Position < len(m.tab.Content[m.cursor.String])-1 {
+				m.cursor.Position++
 			}
+		}
 
-		// Delete key works in normal mode
-		case "x":
-			if m.editMode == models.EditNormal {
-				m.deleteCharAt(m.cursor)
-				m.changed = true
-			}
+	// Toggle system wrap in normal mode
+	case "W":
+		if m.editMode == models.EditNormal {
+			m.wrap = !m.wrap
+		}
 
-		// Backspace works in insert mode
-		case "backspace", "ctrl+h":
-			if m.editMode == models.EditInsert && m.cursor.Position > 0 {
-				m.cursor.Position--
-				m.deleteCharAt(m.cursor)
-				m.changed = true
-			}
+	// Search repeat works in normal mode
+	case "n", "N":
+		if m.editMode == models.EditNormal {
+			m.searchNext(key == "N")
 		}
-	}
 
-	var cmd tea.Cmd
-	m.viewport, cmd = m.viewport.Update(msg)
-	return m, cmd
+	// Delete key works in normal mode
+	case "x":
+		if m.editMode == models.EditNormal {
+			m.deleteCharAt(m.cursor)
+			m.changed = true
+		}
+
+	// Backspace works in insert mode
+	case "backspace", "ctrl+h":
+		if m.editMode == models.EditInsert && m.cursor.Position > 0 {
+			m.cursor.Position--
+			m.deleteCharAt(m.cursor)
+			m.changed = true
+		}
+	}
 }
 
 func (m *TabEditorModel) insertCharAt(pos models.Position, char rune) {
@@ -151,10 +270,52 @@ func (m *TabEditorModel) deleteCharAt(pos models.Position) {
 }
 
 func (m TabEditorModel) View() string {
+	matchCells := m.searchCells()
+
+	var lines []string
+	if m.wrap {
+		cursorTop := 0
+		for i, system := range m.tab.Systems(m.contentWidth()) {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			if m.cursor.Position >= system.Start && m.cursor.Position < system.End {
+				cursorTop = len(lines)
+			}
+			lines = append(lines, m.renderSystem(system, matchCells)...)
+		}
+
+		// Keep the system holding the cursor on screen
+		offset := 0
+		if bottom := cursorTop + len(m.tab.Content); bottom > m.viewport.Height {
+			offset = bottom - m.viewport.Height
+		}
+		m.viewport.SetContent(strings.Join(lines, "\n"))
+		m.viewport.SetYOffset(offset)
+	} else {
+		end := m.xOffset + m.contentWidth()
+		if length := m.tab.Length(); end > length {
+			end = length
+		}
+		lines = m.renderSystem(models.Span{Start: m.xOffset, End: end}, matchCells)
+		m.viewport.SetContent(strings.Join(lines, "\n"))
+	}
+
+	if line := m.searchLine(); line != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("13")).Render(line))
+	}
+	return m.viewport.View()
+}
+
+// renderSystem renders the columns of span as six labelled string lines.
+// Edges that cut off more content are drawn as < and > instead of bar lines.
+func (m TabEditorModel) renderSystem(span models.Span, matchCells map[models.Position]bool) []string {
 	var lines []string
 
 	// String labels (high to low pitch, matching guitar orientation)
-	stringLabels := []string{"e", "B", "G", "D", "A", "E"}
+	stringLabels := m.tab.TuningLabels()
+	edgeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
 
 	// Helper to check if position is highlighted
 	isHighlighted := func(str, pos int) bool {
@@ -166,18 +327,31 @@ func (m TabEditorModel) View() string {
 		return false
 	}
 
+	leftEdge, rightEdge := "|", "|"
+	if !m.wrap {
+		if span.Start > 0 {
+			leftEdge = "<"
+		}
+		if span.End < m.tab.Length() {
+			rightEdge = ">"
+		}
+	}
+
 	for i, label := range stringLabels {
-		line := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("14")).
-			Render(label + "|")
+		line := edgeStyle.Render(label + leftEdge)
 
 		// Render tab content with cursor and playback highlighting
-		content := m.tab.Content[i]
-		for pos, char := range content {
+		content := []rune(m.tab.Content[i])
+		for pos := span.Start; pos < span.End; pos++ {
+			char := ' '
+			if pos < len(content) {
+				char = content[pos]
+			}
+
 			style := lipgloss.NewStyle()
 
 			// Highlight cursor position (takes precedence)
-			if m.cursor.String == i && m.cursor.Position == pos {
+			if !m.preview && m.cursor.String == i && m.cursor.Position == pos {
 				if m.editMode == models.EditInsert {
 					style = style.Background(lipgloss.Color("11")).Foreground(lipgloss.Color("0"))
 				} else {
@@ -186,22 +360,63 @@ func (m TabEditorModel) View() string {
 			} else if isHighlighted(i, pos) {
 				// Highlight playback positions with cyan background
 				style = style.Background(lipgloss.Color("37")).Foreground(lipgloss.Color("0"))
+			} else if current, ok := matchCells[models.Position{String: i, Position: pos}]; ok {
+				// Highlight search matches, the one under the cursor brighter
+				if current {
+					style = style.Background(lipgloss.Color("13")).Foreground(lipgloss.Color("0"))
+				} else {
+					style = style.Background(lipgloss.Color("5")).Foreground(lipgloss.Color("15"))
+				}
 			}
 
 			line += style.Render(string(char))
 		}
 
-		line += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("14")).
-			Render("|")
+		line += edgeStyle.Render(rightEdge)
 
 		lines = append(lines, line)
 	}
 
-	content := strings.Join(lines, "\n")
-	m.viewport.SetContent(content)
+	return lines
+}
 
-	return m.viewport.View()
+// contentWidth is the number of tab columns that fit beside the string
+// labels and edges.
+func (m TabEditorModel) contentWidth() int {
+	if m.width <= 0 {
+		return 76
+	}
+	if w := m.width - 4; w > 8 {
+		return w
+	}
+	return 8
+}
+
+// scrollToCursor moves the horizontal window so the cursor stays visible
+// with a few columns of context on either side.
+func (m *TabEditorModel) scrollToCursor() {
+	if m.tab == nil {
+		return
+	}
+
+	cols := m.contentWidth()
+	margin := 4
+	if margin > cols/4 {
+		margin = cols / 4
+	}
+
+	if m.cursor.Position < m.xOffset+margin {
+		m.xOffset = m.cursor.Position - margin
+	}
+	if m.cursor.Position >= m.xOffset+cols-margin {
+		m.xOffset = m.cursor.Position - cols + margin + 1
+	}
+	if maxOffset := m.tab.Length() - cols; m.xOffset > maxOffset {
+		m.xOffset = maxOffset
+	}
+	if m.xOffset < 0 {
+		m.xOffset = 0
+	}
 }
 
 func (m TabEditorModel) HasChanged() bool {
@@ -227,3 +442,12 @@ func (m TabEditorModel) GetEditMode() models.EditMode {
 func (m TabEditorModel) GetCursor() models.Position {
 	return m.cursor
 }
+
+// SetWrap turns system wrapping on or off.
+func (m *TabEditorModel) SetWrap(wrap bool) {
+	m.wrap = wrap
+}
+
+func (m TabEditorModel) Wrap() bool {
+	return m.wrap
+}
@@ -0,0 +1,333 @@
+// internal/ui/files.go
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/Cod-e-Codes/tuitar/internal/export"
+	"github.com/Cod-e-Codes/tuitar/internal/importer"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// exportPrefsKey is the setting holding the layout of exported tabs.
+const exportPrefsKey = "export.ascii"
+
+// lilyPondPrefsKey is the setting holding what LilyPond scores print.
+const lilyPondPrefsKey = "export.lilypond"
+
+// exportFormatKey is the setting holding the format tabs are exported in.
+const exportFormatKey = "export.format"
+
+// importTuningKey is the setting holding the tuning imported notes that
+// have only a pitch are fingered for.
+const importTuningKey = "import.tuning"
+
+// importTunings are the tunings Ctrl+T cycles through in the import dialog,
+// lowest string first. The first, standard tuning, is the default.
+var importTunings = []struct{ name, tuning string }{
+	{"Standard", "E A D G B e"},
+	{"Drop D", "D A D G B e"},
+	{"Eb standard", "Eb Ab Db Gb Bb eb"},
+	{"D standard", "D G C F A d"},
+	{"Drop C", "C G C F A d"},
+	{"Open G", "D G D G B d"},
+	{"Open D", "D A D F# A d"},
+	{"DADGAD", "D A D G A d"},
+}
+
+// exportWidths are the line widths Tab cycles through in the export
+// dialog; 0 never wraps.
+var exportWidths = []int{60, 80, 100, 120, 0}
+
+// maxProblemsShown caps the import problems listed in the dialog.
+const maxProblemsShown = 8
+
+// importFile adds the tabs in the file at path to the library. A file
+// holding several tracks asks which to add first. Lines the importer could
+// not use are listed in a dialog afterwards.
+func (m *Model) importFile(path string) {
+	var opts importer.Options
+	opts.Tuning, _ = models.ParseTuning(importTunings[m.importTuning].tuning)
+	tabs, problems, err := importer.ReadFile(strings.TrimSpace(path), opts)
+	if err != nil {
+		m.statusBar.SetStatus("Error importing " + path + ": " + err.Error())
+		return
+	}
+	if len(tabs) == 1 {
+		m.saveImported(tabs, problems)
+		return
+	}
+
+	var choices []dialogChoice
+	for i, tab := range tabs {
+		if i == 9 {
+			break
+		}
+		tab := tab
+		choices = append(choices, dialogChoice{key: fmt.Sprint(i + 1), label: tab.Name, action: func(m *Model) tea.Cmd {
+			m.saveImported([]*models.Tab{tab}, problems)
+			return nil
+		}})
+	}
+	choices = append(choices,
+		dialogChoice{key: "a", label: fmt.Sprintf("All %d", len(tabs)), action: func(m *Model) tea.Cmd {
+			m.saveImported(tabs, problems)
+			return nil
+		}},
+		dialogChoice{key: "esc", label: "Cancel", action: func(m *Model) tea.Cmd {
+			m.statusBar.SetStatus("Cancelled")
+			return nil
+		}},
+	)
+	m.confirm = &confirmDialog{
+		title:   "Import",
+		prompt:  fmt.Sprintf("%s has %d tracks. Import which?", filepath.Base(strings.TrimSpace(path)), len(tabs)),
+		choices: choices,
+	}
+}
+
+// saveImported adds imported tabs to the library and lists the problems
+// found reading them.
+func (m *Model) saveImported(tabs []*models.Tab, problems []importer.Problem) {
+	for _, tab := range tabs {
+		if err := m.storage.SaveTab(tab); err != nil {
+			m.statusBar.SetStatus("Error saving imported tab: " + err.Error())
+			return
+		}
+	}
+	m.refreshTabs()
+
+	imported := tabs[0].Name
+	if len(tabs) > 1 {
+		imported = fmt.Sprintf("%d tabs", len(tabs))
+	}
+	m.statusBar.SetStatus("Imported " + imported)
+
+	if len(problems) == 0 {
+		return
+	}
+
+	lines := []string{fmt.Sprintf("Imported %s with %d %s:", imported, len(problems), models.Plural(len(problems), "problem")), ""}
+	for i, p := range problems {
+		if i == maxProblemsShown {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(problems)-i))
+			break
+		}
+		lines = append(lines, p.String())
+	}
+	m.confirm = &confirmDialog{
+		title:  "Import",
+		prompt: strings.Join(lines, "\n"),
+		choices: []dialogChoice{
+			{key: "esc", label: "Close", action: func(m *Model) tea.Cmd { return nil }},
+		},
+	}
+}
+
+// updateImportOptions handles the tuning key of the import dialog and
+// reports whether the key was it.
+func (m *Model) updateImportOptions(key string) bool {
+	if key != "ctrl+t" {
+		return false
+	}
+	m.importTuning = (m.importTuning + 1) % len(importTunings)
+	if err := m.storage.SetSetting(importTuningKey, importTunings[m.importTuning].tuning); err != nil {
+		m.statusBar.SetStatus("Error saving import settings: " + err.Error())
+	}
+	return true
+}
+
+func (m Model) importOptionsLine() string {
+	tuning := importTunings[m.importTuning]
+	return fmt.Sprintf("Ctrl+T: %s (%s) for notes without a fret", tuning.name, tuning.tuning)
+}
+
+// openExport asks where to write tabs: a file for one tab, a directory for
+// several.
+func (m *Model) openExport(tabs []models.Tab) {
+	m.exportTargets = tabs
+	m.inputMode = inputModeExport
+	if len(tabs) == 1 {
+		m.textInput.SetValue(fileName(tabs[0].Name) + m.exportFormat.Ext())
+	} else {
+		m.textInput.SetValue(".")
+	}
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+}
+
+// updateExportOptions handles the format key of the export dialog and the
+// option keys of the chosen format. It reports whether the key was one of
+// them.
+func (m *Model) updateExportOptions(key string) bool {
+	if key == "ctrl+f" {
+		m.cycleExportFormat()
+		return true
+	}
+
+	if m.exportFormat == export.FormatLilyPond {
+		if key != "ctrl+o" {
+			return false
+		}
+		opts := &m.exportOptions.LilyPond
+		opts.Notation = !opts.Notation
+		data, _ := json.Marshal(opts)
+		if err := m.storage.SetSetting(lilyPondPrefsKey, string(data)); err != nil {
+			m.statusBar.SetStatus("Error saving export settings: " + err.Error())
+		}
+		return true
+	}
+	if m.exportFormat != export.FormatText {
+		return false
+	}
+
+	opts := &m.exportOptions.ASCII
+	switch key {
+	case "tab":
+		next := 0
+		for i, width := range exportWidths {
+			if width == opts.Width {
+				next = (i + 1) % len(exportWidths)
+			}
+		}
+		opts.Width = exportWidths[next]
+	case "ctrl+n":
+		opts.MeasureNumbers = !opts.MeasureNumbers
+	case "ctrl+t":
+		opts.Header = !opts.Header
+	default:
+		return false
+	}
+
+	data, _ := json.Marshal(opts)
+	if err := m.storage.SetSetting(exportPrefsKey, string(data)); err != nil {
+		m.statusBar.SetStatus("Error saving export settings: " + err.Error())
+	}
+	return true
+}
+
+// cycleExportFormat switches to the next export format, changing the
+// extension of a single tab's file name to match.
+func (m *Model) cycleExportFormat() {
+	next := 0
+	for i, format := range export.Formats {
+		if format == m.exportFormat {
+			next = (i + 1) % len(export.Formats)
+		}
+	}
+	m.exportFormat = export.Formats[next]
+
+	if len(m.exportTargets) == 1 {
+		path := m.textInput.Value()
+		m.textInput.SetValue(strings.TrimSuffix(path, filepath.Ext(path)) + m.exportFormat.Ext())
+		m.textInput.CursorEnd()
+	}
+	if err := m.storage.SetSetting(exportFormatKey, string(m.exportFormat)); err != nil {
+		m.statusBar.SetStatus("Error saving export settings: " + err.Error())
+	}
+}
+
+func (m Model) exportOptionsLine() string {
+	format := "Ctrl+F: " + m.exportFormat.String()
+	onOff := func(on bool) string {
+		if on {
+			return "on"
+		}
+		return "off"
+	}
+	switch m.exportFormat {
+	case export.FormatLilyPond:
+		return fmt.Sprintf("%s • Ctrl+O: notation staff %s", format, onOff(m.exportOptions.LilyPond.Notation))
+	case export.FormatMusicXML, export.FormatSVG, export.FormatPDF, export.FormatHTML, export.FormatChordPro:
+		return format
+	}
+
+	opts := m.exportOptions.ASCII
+	width := "no wrap"
+	if opts.Width > 0 {
+		width = fmt.Sprintf("width %d", opts.Width)
+	}
+	return fmt.Sprintf("%s • Tab: %s • Ctrl+N: measure numbers %s • Ctrl+T: header %s",
+		format, width, onOff(opts.MeasureNumbers), onOff(opts.Header))
+}
+
+// exportTabs writes the export targets to path. A single tab's format
+// follows the extension of path; several tabs go into the directory path in
+// the chosen format, numbered "_2", "_3" and so on where their names would
+// make the same file.
+func (m *Model) exportTabs(path string) {
+	tabs := m.exportTargets
+	path = strings.TrimSpace(path)
+
+	format := export.FormatFor(path, m.exportFormat)
+	paths := []string{path}
+	if len(tabs) > 1 {
+		format = m.exportFormat
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			m.statusBar.SetStatus("Error exporting: " + err.Error())
+			return
+		}
+		paths = paths[:0]
+		used := make(map[string]bool) // lower case, for case-insensitive file systems
+		for _, tab := range tabs {
+			name := fileName(tab.Name)
+			for n := 2; used[strings.ToLower(name)]; n++ {
+				name = fmt.Sprintf("%s_%d", fileName(tab.Name), n)
+			}
+			used[strings.ToLower(name)] = true
+			paths = append(paths, filepath.Join(path, name+format.Ext()))
+		}
+	}
+
+	for i := range tabs {
+		if err := writeFile(paths[i], func(f *os.File) error {
+			return export.Write(f, &tabs[i], format, m.exportOptions)
+		}); err != nil {
+			m.statusBar.SetStatus("Error exporting " + tabs[i].Name + ": " + err.Error())
+			return
+		}
+	}
+
+	if len(tabs) == 1 {
+		m.statusBar.SetStatus("Exported " + tabs[0].Name + " to " + path)
+	} else {
+		m.statusBar.SetStatus(fmt.Sprintf("Exported %d tabs to %s", len(tabs), path))
+	}
+}
+
+// writeFile creates the file at path and fills it with write.
+func writeFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// fileName turns a name into a safe file name, keeping letters, digits,
+// dashes and underscores.
+func fileName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "untitled"
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+// internal/ui/history.go
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+	"github.com/Cod-e-Codes/tuitar/internal/ui/components"
+)
+
+// openHistory switches to the revision history of tab. Tabs that were never
+// saved have none.
+func (m *Model) openHistory(tab *models.Tab) {
+	if tab == nil || tab.ID == 0 {
+		m.statusBar.SetStatus("Save the tab to start its history")
+		return
+	}
+
+	revisions, err := m.storage.LoadRevisions(tab.ID)
+	if err != nil {
+		m.statusBar.SetStatus("Error loading history: " + err.Error())
+		return
+	}
+
+	if m.state.ViewMode != models.ViewHistory {
+		m.historyReturn = m.state.ViewMode
+	}
+	m.history = components.NewHistory(*tab, revisions)
+	if m.windowSize.Width > 0 {
+		m.history.SetSize(m.windowSize.Width, m.windowSize.Height-5)
+	}
+	m.state.ViewMode = models.ViewHistory
+	m.statusBar.SetStatus(fmt.Sprintf("%d %s of %s", len(revisions), models.Plural(len(revisions), "revision"), tab.Name))
+}
+
+// restoreRevision saves an old revision as the current version of its tab.
+// The restore is itself a new revision, so it can be undone the same way.
+func (m Model) restoreRevision(rev models.Revision) (tea.Model, tea.Cmd) {
+	number := 0
+	if revisions, err := m.storage.LoadRevisions(rev.TabID); err == nil {
+		for i, r := range revisions {
+			if r.ID == rev.ID {
+				number = len(revisions) - i
+			}
+		}
+	}
+
+	restore := func(m *Model) tea.Cmd {
+		stored, err := m.storage.LoadTab(rev.TabID)
+		if err != nil {
+			m.statusBar.SetStatus("Error restoring revision: " + err.Error())
+			return nil
+		}
+
+		tab := rev.Tab
+		tab.ID = stored.ID
+		tab.CreatedAt = stored.CreatedAt
+		tab.DeletedAt = stored.DeletedAt
+		tab.Favorite = stored.Favorite
+		tab.Tags = stored.Tags
+		if err := m.storage.SaveTab(&tab); err != nil {
+			m.statusBar.SetStatus("Error restoring revision: " + err.Error())
+			return nil
+		}
+
+		if current := m.state.CurrentTab; current != nil && current.ID == tab.ID {
+			m.openEditor(&tab)
+			m.state.ViewMode = models.ViewHistory
+		}
+		m.refreshTabs()
+		m.openHistory(&tab)
+		m.statusBar.SetStatus(fmt.Sprintf("Restored revision #%d of %s", number, tab.Name))
+		return nil
+	}
+
+	// Only the open tab can have edits that the restore would replace
+	if current := m.state.CurrentTab; current == nil || current.ID != rev.TabID {
+		cmd := restore(&m)
+		return m, cmd
+	}
+	return m.guardUnsaved(fmt.Sprintf("Save changes before restoring revision #%d?", number), restore)
+}
+
+func (m Model) renderHistory() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		Render("Tuitar - History")
+
+	help := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Render("j/k: Select • b: Compare with • J/K: Scroll • Enter/r: Restore • Esc: Back • Ctrl+C: Quit")
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		m.history.View(),
+		"",
+		help,
+	)
+}
@@ -0,0 +1,328 @@
+// internal/ui/library.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+	"github.com/Cod-e-Codes/tuitar/internal/ui/components"
+)
+
+// handleBrowserAction carries out a delete, duplicate, rename, tag edit,
+// favorite toggle, setlist addition, export, restore or purge requested
+// from the browser.
+func (m Model) handleBrowserAction(msg components.BrowserActionMsg) (tea.Model, tea.Cmd) {
+	switch msg.Action {
+	case "delete":
+		// The open tab's unsaved edits would be lost with it; the trash
+		// only keeps what was saved
+		if current := m.state.CurrentTab; current != nil {
+			for _, tab := range msg.Tabs {
+				if tab.ID == current.ID {
+					return m.guardUnsaved("Save changes to "+current.Name+" before moving it to the trash?", func(m *Model) tea.Cmd {
+						m.confirmDelete(msg.Tabs)
+						return nil
+					})
+				}
+			}
+		}
+		m.confirmDelete(msg.Tabs)
+	case "duplicate":
+		m.duplicateTabs(msg.Tabs)
+	case "favorite":
+		m.toggleFavorite(msg.Tabs)
+	case "tags":
+		m.tagTargets = msg.Tabs
+		m.knownTags, _ = m.storage.ListTags()
+		m.inputMode = inputModeTags
+		m.textInput.Placeholder = "learning, setlist"
+		if len(msg.Tabs) == 1 {
+			m.textInput.SetValue(strings.Join(msg.Tabs[0].Tags, ", "))
+		}
+		m.textInput.CursorEnd()
+		m.textInput.Focus()
+	case "export":
+		m.openExport(msg.Tabs)
+	case "setlist":
+		return m.addToSetlist(msg.Tabs)
+	case "restore":
+		m.restoreTabs(msg.Tabs)
+	case "purge":
+		m.confirmPurge(msg.Tabs)
+	case "rename":
+		tab := msg.Tabs[0]
+		m.renameTarget = &tab
+		m.inputMode = inputModeRename
+		m.textInput.SetValue(tab.Name)
+		m.textInput.CursorEnd()
+		m.textInput.Focus()
+	}
+	return m, nil
+}
+
+func (m *Model) confirmDelete(tabs []models.Tab) {
+	prompt := fmt.Sprintf("Move %q to the trash?", tabs[0].Name)
+	if len(tabs) > 1 {
+		prompt = fmt.Sprintf("Move %d tabs to the trash?", len(tabs))
+	}
+
+	m.confirm = &confirmDialog{
+		title:  "Delete",
+		prompt: prompt,
+		choices: []dialogChoice{
+			{key: "y", label: "Move to trash", action: func(m *Model) tea.Cmd {
+				m.deleteTabs(tabs)
+				return nil
+			}},
+			{key: "n", label: "Keep", action: func(m *Model) tea.Cmd {
+				m.statusBar.SetStatus("Cancelled")
+				return nil
+			}},
+		},
+	}
+}
+
+func (m *Model) deleteTabs(tabs []models.Tab) {
+	deleted := 0
+	for _, tab := range tabs {
+		if err := m.storage.DeleteTab(tab.ID); err != nil {
+			m.statusBar.SetStatus("Error deleting " + tab.Name + ": " + err.Error())
+			break
+		}
+		deleted++
+
+		// The deleted tab's edits have nowhere to go
+		if current := m.state.CurrentTab; current != nil && current.ID == tab.ID {
+			m.state.CurrentTab = nil
+			m.state.Modified = false
+			m.clearJournal()
+		}
+	}
+
+	m.tabBrowser.ClearMarks()
+	m.refreshTabs()
+	if deleted == len(tabs) {
+		m.statusBar.SetStatus(fmt.Sprintf("Moved %d %s to the trash (t to view)", deleted, models.Plural(deleted, "tab")))
+	}
+}
+
+func (m *Model) restoreTabs(tabs []models.Tab) {
+	restored := 0
+	for _, tab := range tabs {
+		if err := m.storage.RestoreTab(tab.ID); err != nil {
+			m.statusBar.SetStatus("Error restoring " + tab.Name + ": " + err.Error())
+			break
+		}
+		restored++
+	}
+
+	m.tabBrowser.ClearMarks()
+	m.refreshTabs()
+	if restored == len(tabs) {
+		m.statusBar.SetStatus(fmt.Sprintf("Restored %d %s", restored, models.Plural(restored, "tab")))
+	}
+}
+
+func (m *Model) confirmPurge(tabs []models.Tab) {
+	prompt := fmt.Sprintf("Delete %q forever? This cannot be undone.", tabs[0].Name)
+	if len(tabs) > 1 {
+		prompt = fmt.Sprintf("Delete %d tabs forever? This cannot be undone.", len(tabs))
+	}
+
+	m.confirm = &confirmDialog{
+		title:  "Empty trash",
+		prompt: prompt,
+		choices: []dialogChoice{
+			{key: "y", label: "Delete forever", action: func(m *Model) tea.Cmd {
+				m.purgeTabs(tabs)
+				return nil
+			}},
+			{key: "n", label: "Keep", action: func(m *Model) tea.Cmd {
+				m.statusBar.SetStatus("Cancelled")
+				return nil
+			}},
+		},
+	}
+}
+
+func (m *Model) purgeTabs(tabs []models.Tab) {
+	purged := 0
+	for _, tab := range tabs {
+		if err := m.storage.PurgeTab(tab.ID); err != nil {
+			m.statusBar.SetStatus("Error deleting " + tab.Name + ": " + err.Error())
+			break
+		}
+		purged++
+	}
+
+	m.tabBrowser.ClearMarks()
+	m.refreshTabs()
+	if purged == len(tabs) {
+		m.statusBar.SetStatus(fmt.Sprintf("Deleted %d %s forever", purged, models.Plural(purged, "tab")))
+	}
+}
+
+func (m *Model) duplicateTabs(tabs []models.Tab) {
+	duplicated := 0
+	for _, tab := range tabs {
+		tab.ID = 0
+		tab.Name += " (copy)"
+		tab.CreatedAt = time.Now()
+		if err := m.storage.SaveTab(&tab); err != nil {
+			m.statusBar.SetStatus("Error duplicating " + tab.Name + ": " + err.Error())
+			break
+		}
+		// SaveTab leaves tags and the favorite flag alone; copy them over
+		if err := m.copyLabels(&tab); err != nil {
+			m.statusBar.SetStatus("Error duplicating " + tab.Name + ": " + err.Error())
+			break
+		}
+		duplicated++
+	}
+
+	m.tabBrowser.ClearMarks()
+	m.refreshTabs()
+	if duplicated == len(tabs) {
+		m.statusBar.SetStatus(fmt.Sprintf("Duplicated %d %s", duplicated, models.Plural(duplicated, "tab")))
+	}
+}
+
+// copyLabels gives a saved copy the tags and favorite flag of the tab it
+// was copied from, which it still carries.
+func (m *Model) copyLabels(tab *models.Tab) error {
+	for _, tag := range tab.Tags {
+		if err := m.storage.AddTag(tab.ID, tag); err != nil {
+			return err
+		}
+	}
+	if tab.Favorite {
+		return m.storage.SetFavorite(tab.ID, true)
+	}
+	return nil
+}
+
+// renameTab saves the tab picked for renaming under name. The open tab is
+// renamed in place so its unsaved edits are kept.
+func (m *Model) renameTab(name string) {
+	tab := m.renameTarget
+	m.renameTarget = nil
+	if tab == nil {
+		return
+	}
+
+	if current := m.state.CurrentTab; current != nil && current.ID == tab.ID {
+		current.Name = name
+		if m.state.Modified {
+			// Only the name goes to storage; the rest waits for a save
+			stored := *tab
+			stored.Name = name
+			tab = &stored
+		} else {
+			tab = current
+		}
+	} else {
+		tab.Name = name
+	}
+
+	if err := m.storage.SaveTab(tab); err != nil {
+		m.statusBar.SetStatus("Error renaming tab: " + err.Error())
+		return
+	}
+	m.refreshTabs()
+	m.statusBar.SetStatus("Renamed to " + name)
+}
+
+// toggleFavorite marks the tabs as favorites, or unmarks them if they all
+// are already.
+func (m *Model) toggleFavorite(tabs []models.Tab) {
+	favorite := false
+	for _, tab := range tabs {
+		if !tab.Favorite {
+			favorite = true
+		}
+	}
+
+	for _, tab := range tabs {
+		if err := m.storage.SetFavorite(tab.ID, favorite); err != nil {
+			m.statusBar.SetStatus("Error updating favorites: " + err.Error())
+			return
+		}
+		if current := m.state.CurrentTab; current != nil && current.ID == tab.ID {
+			current.Favorite = favorite
+		}
+	}
+
+	m.refreshTabs()
+	if favorite {
+		m.statusBar.SetStatus(fmt.Sprintf("Added %d %s to favorites", len(tabs), models.Plural(len(tabs), "tab")))
+	} else {
+		m.statusBar.SetStatus(fmt.Sprintf("Removed %d %s from favorites", len(tabs), models.Plural(len(tabs), "tab")))
+	}
+}
+
+// applyTags saves the tag editor's comma-separated list. For a single tab
+// the list replaces its tags; for several it adds each tag to all of them,
+// and "-tag" removes one.
+func (m *Model) applyTags(value string) {
+	var add, remove []string
+	for _, field := range strings.Split(value, ",") {
+		tag := strings.TrimSpace(field)
+		switch {
+		case tag == "" || tag == "-":
+		case tag[0] == '-':
+			remove = append(remove, strings.TrimSpace(tag[1:]))
+		default:
+			add = append(add, tag)
+		}
+	}
+
+	for _, tab := range m.tagTargets {
+		drop := remove
+		if len(m.tagTargets) == 1 {
+			// Anything left out of the list goes
+			for _, old := range tab.Tags {
+				kept := false
+				for _, tag := range add {
+					kept = kept || strings.EqualFold(tag, old)
+				}
+				if !kept {
+					drop = append(drop, old)
+				}
+			}
+		}
+
+		for _, tag := range drop {
+			if err := m.storage.RemoveTag(tab.ID, tag); err != nil {
+				m.statusBar.SetStatus("Error removing tag: " + err.Error())
+				return
+			}
+		}
+		for _, tag := range add {
+			if err := m.storage.AddTag(tab.ID, tag); err != nil {
+				m.statusBar.SetStatus("Error adding tag: " + err.Error())
+				return
+			}
+		}
+	}
+
+	m.tabBrowser.ClearMarks()
+	m.refreshTabs()
+	if len(m.tagTargets) == 1 {
+		m.statusBar.SetStatus("Tagged " + m.tagTargets[0].Name + ": " + strings.Join(add, ", "))
+	} else {
+		m.statusBar.SetStatus(fmt.Sprintf("Updated tags of %d tabs", len(m.tagTargets)))
+	}
+}
+
+// knownTagsLine lists the existing tags as suggestions in the tag editor.
+func (m Model) knownTagsLine() string {
+	var names []string
+	for _, tag := range m.knownTags {
+		names = append(names, fmt.Sprintf("%s (%d)", tag.Name, tag.Count))
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("Known: " + strings.Join(names, ", "))
+}
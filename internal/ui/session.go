@@ -0,0 +1,283 @@
+// internal/ui/session.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+)
+
+// confirmAction runs once the user has picked a choice in a dialog. It may
+// open another dialog by setting m.confirm.
+type confirmAction func(m *Model) tea.Cmd
+
+type dialogChoice struct {
+	key    string
+	label  string
+	action confirmAction
+}
+
+// confirmDialog is a modal question answered with a single key. Esc picks
+// the "esc" choice if there is one and otherwise just closes the dialog.
+type confirmDialog struct {
+	title   string
+	prompt  string
+	choices []dialogChoice
+}
+
+type autosaveMsg time.Time
+
+// WithAutosave returns a copy of the model that saves modified tabs to
+// storage every interval. Zero disables autosave.
+func (m Model) WithAutosave(interval time.Duration) Model {
+	m.autosave = interval
+	return m
+}
+
+func (m Model) autosaveTick() tea.Cmd {
+	if m.autosave <= 0 {
+		return nil
+	}
+	return tea.Tick(m.autosave, func(t time.Time) tea.Msg {
+		return autosaveMsg(t)
+	})
+}
+
+// handleAutosave saves the current tab if it has unsaved edits. Tabs that
+// were never saved are left alone so autosave does not create rows named
+// "New Tab".
+func (m Model) handleAutosave() (tea.Model, tea.Cmd) {
+	tab := m.state.CurrentTab
+	if m.state.Modified && tab != nil && tab.ID != 0 {
+		if err := m.storage.SaveTab(tab); err != nil {
+			m.statusBar.SetStatus("Autosave failed: " + err.Error())
+		} else {
+			m.markSaved()
+			m.statusBar.SetStatus("Autosaved: " + tab.Name)
+		}
+	}
+	return m, m.autosaveTick()
+}
+
+// markSaved records that the current tab now matches storage.
+func (m *Model) markSaved() {
+	m.state.Modified = false
+	m.clearJournal()
+	m.refreshTabs()
+}
+
+// guardUnsaved runs action immediately when there is nothing to lose, and
+// otherwise asks whether to save the current tab first.
+func (m Model) guardUnsaved(prompt string, action confirmAction) (tea.Model, tea.Cmd) {
+	if !m.state.Modified || m.state.CurrentTab == nil {
+		cmd := action(&m)
+		return m, cmd
+	}
+
+	m.confirm = &confirmDialog{
+		title:  "Unsaved changes",
+		prompt: prompt,
+		choices: []dialogChoice{
+			{key: "y", label: "Save", action: func(m *Model) tea.Cmd {
+				if !m.saveCurrentTab() {
+					return nil
+				}
+				return action(m)
+			}},
+			{key: "n", label: "Discard", action: func(m *Model) tea.Cmd {
+				m.state.Modified = false
+				m.clearJournal()
+				return action(m)
+			}},
+		},
+	}
+	return m, nil
+}
+
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	pressed := msg.String()
+	if pressed == "ctrl+c" {
+		pressed = "esc"
+	}
+
+	for _, choice := range m.confirm.choices {
+		if choice.key == strings.ToLower(pressed) {
+			m.confirm = nil
+			cmd := choice.action(&m)
+			return m, cmd
+		}
+	}
+
+	if pressed == "esc" {
+		m.confirm = nil
+		m.statusBar.SetStatus("Cancelled")
+	}
+	return m, nil
+}
+
+func (m Model) renderConfirmDialog() string {
+	var hints string
+	for i, choice := range m.confirm.choices {
+		if i > 0 {
+			hints += " • "
+		}
+		hints += fmt.Sprintf("%s: %s", displayKey(choice.key), choice.label)
+	}
+	if !m.hasEscChoice() {
+		hints += " • Esc: Cancel"
+	}
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("11")).
+		Padding(1, 2).
+		Width(56).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Bold(true).Render(m.confirm.title),
+			"",
+			m.confirm.prompt,
+			"",
+			lipgloss.NewStyle().Faint(true).Render(hints),
+		))
+
+	return lipgloss.Place(m.windowSize.Width, m.windowSize.Height,
+		lipgloss.Center, lipgloss.Center, dialog)
+}
+
+func (m Model) hasEscChoice() bool {
+	for _, choice := range m.confirm.choices {
+		if choice.key == "esc" {
+			return true
+		}
+	}
+	return false
+}
+
+func displayKey(k string) string {
+	if k == "esc" {
+		return "Esc"
+	}
+	return strings.ToUpper(k)
+}
+
+// writeJournal snapshots the current tab into the recovery journal so its
+// edits survive a crash before the next save.
+func (m *Model) writeJournal() {
+	tab := m.state.CurrentTab
+	if tab == nil {
+		return
+	}
+
+	entry := &models.JournalEntry{ID: m.journalID, TabID: tab.ID, Tab: *tab}
+	if err := m.storage.WriteJournal(entry); err != nil {
+		m.statusBar.SetStatus("Recovery journal error: " + err.Error())
+		return
+	}
+	m.journalID = entry.ID
+}
+
+func (m *Model) clearJournal() {
+	if m.journalID == 0 {
+		return
+	}
+	if err := m.storage.ClearJournal(m.journalID); err != nil {
+		m.statusBar.SetStatus("Recovery journal error: " + err.Error())
+		return
+	}
+	m.journalID = 0
+}
+
+// recoveryTarget points recovered edits at the tab they were made to, and
+// returns how the status should describe it. A tab purged since becomes a
+// new one, and a tab in the trash is restored, so the edits never land
+// where the library cannot show them.
+func (m *Model) recoveryTarget(tab *models.Tab) (string, error) {
+	if tab.ID == 0 {
+		return "", nil
+	}
+	stored, err := m.storage.LoadTab(tab.ID)
+	switch {
+	case err != nil:
+		tab.ID = 0
+		return " as a new tab; the original was deleted", nil
+	case stored.DeletedAt != nil:
+		if err := m.storage.RestoreTab(tab.ID); err != nil {
+			return "", err
+		}
+		return " and restored from the trash", nil
+	}
+	return "", nil
+}
+
+// offerRecovery opens a dialog for the first orphaned journal entry. Each
+// answer moves on to the next entry, except Open, which ends the review.
+// Entries put off with Esc, or left after Open, are offered again on the
+// next start.
+func (m *Model) offerRecovery(entries []models.JournalEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	entry, rest := entries[0], entries[1:]
+
+	name := entry.Tab.Name
+	if entry.TabID == 0 {
+		name += " (never saved)"
+	}
+	prompt := fmt.Sprintf("Unsaved edits to %q from %s were left by a previous session.",
+		name, entry.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	if len(rest) > 0 {
+		prompt += fmt.Sprintf("\n%d more session(s) to review.", len(rest))
+	}
+
+	m.confirm = &confirmDialog{
+		title:  "Recover unsaved edits",
+		prompt: prompt,
+		choices: []dialogChoice{
+			{key: "r", label: "Recover & save", action: func(m *Model) tea.Cmd {
+				tab := entry.Tab
+				tab.ID = entry.TabID
+				where, err := m.recoveryTarget(&tab)
+				if err == nil {
+					err = m.storage.SaveTab(&tab)
+				}
+				if err != nil {
+					m.statusBar.SetStatus("Error recovering tab: " + err.Error())
+				} else {
+					m.storage.ClearJournal(entry.ID)
+					m.refreshTabs()
+					m.statusBar.SetStatus("Recovered" + where + ": " + tab.Name)
+				}
+				m.offerRecovery(rest)
+				return nil
+			}},
+			{key: "o", label: "Open", action: func(m *Model) tea.Cmd {
+				tab := entry.Tab
+				tab.ID = entry.TabID
+				where, err := m.recoveryTarget(&tab)
+				if err != nil {
+					m.statusBar.SetStatus("Error recovering tab: " + err.Error())
+					return nil
+				}
+				m.refreshTabs()
+				m.openEditor(&tab)
+				m.state.Modified = true
+				m.journalID = entry.ID
+				m.statusBar.SetStatus("Recovered edits opened" + where + ": " + tab.Name)
+				return nil
+			}},
+			{key: "d", label: "Discard", action: func(m *Model) tea.Cmd {
+				m.storage.ClearJournal(entry.ID)
+				m.offerRecovery(rest)
+				return nil
+			}},
+			{key: "esc", label: "Later", action: func(m *Model) tea.Cmd {
+				m.offerRecovery(rest)
+				return nil
+			}},
+		},
+	}
+}
@@ -0,0 +1,265 @@
+// internal/ui/setlists.go
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Cod-e-Codes/tuitar/internal/export"
+	"github.com/Cod-e-Codes/tuitar/internal/midi"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
+	"github.com/Cod-e-Codes/tuitar/internal/ui/components"
+)
+
+// setlistTickMsg refreshes the song marker while a setlist plays.
+type setlistTickMsg time.Time
+
+// setlistExportWidth is the line width of exported setlists.
+const setlistExportWidth = 80
+
+// openSetlists switches to the setlist view.
+func (m *Model) openSetlists() tea.Cmd {
+	setlists, err := m.storage.LoadSetlists()
+	if err != nil {
+		m.statusBar.SetStatus("Error loading setlists: " + err.Error())
+		return nil
+	}
+	tabs, _ := m.storage.LoadAllTabs()
+
+	m.setlists.SetSetlists(setlists)
+	m.setlists.SetTabs(tabs)
+	if m.windowSize.Width > 0 {
+		m.setlists.SetSize(m.windowSize.Width, m.windowSize.Height-5)
+	}
+	m.state.ViewMode = models.ViewSetlist
+	m.statusBar.SetStatus(fmt.Sprintf("%d %s", len(setlists), models.Plural(len(setlists), "setlist")))
+	return nil
+}
+
+// reloadSetlists refreshes the setlist view after a change in storage.
+func (m *Model) reloadSetlists() {
+	if setlists, err := m.storage.LoadSetlists(); err == nil {
+		m.setlists.SetSetlists(setlists)
+	}
+}
+
+func (m *Model) saveSetlist(setlist models.Setlist) {
+	isNew := setlist.ID == 0
+	if err := m.storage.SaveSetlist(&setlist); err != nil {
+		m.statusBar.SetStatus("Error saving setlist: " + err.Error())
+		return
+	}
+
+	m.reloadSetlists()
+	if isNew {
+		m.setlists.Select(setlist.ID)
+		m.statusBar.SetStatus("Created setlist " + setlist.Name + "; press a to add songs")
+	}
+}
+
+// handleSetlistAction deletes, exports, plays or stops a setlist, or goes to
+// the browser to pick songs for it.
+func (m Model) handleSetlistAction(msg components.SetlistActionMsg) (tea.Model, tea.Cmd) {
+	setlist := msg.Setlist
+	switch msg.Action {
+	case "delete":
+		m.confirm = &confirmDialog{
+			title:  "Delete",
+			prompt: fmt.Sprintf("Delete setlist %q? Its tabs stay in the library.", setlist.Name),
+			choices: []dialogChoice{
+				{key: "y", label: "Delete", action: func(m *Model) tea.Cmd {
+					if err := m.storage.DeleteSetlist(setlist.ID); err != nil {
+						m.statusBar.SetStatus("Error deleting setlist: " + err.Error())
+						return nil
+					}
+					m.reloadSetlists()
+					m.statusBar.SetStatus("Deleted setlist " + setlist.Name)
+					return nil
+				}},
+				{key: "n", label: "Keep", action: func(m *Model) tea.Cmd {
+					m.statusBar.SetStatus("Cancelled")
+					return nil
+				}},
+			},
+		}
+	case "export":
+		m.exportSetlist(&setlist)
+	case "play":
+		return m, m.playSetlist(&setlist, msg.From)
+	case "stop":
+		if m.midiPlayer.IsPlaying() {
+			m.midiPlayer.Stop()
+			m.statusBar.SetStatus("Playback stopped")
+		}
+	case "add":
+		m.setlistTarget = setlist.ID
+		m.state.ViewMode = models.ViewBrowser
+		m.statusBar.SetStatus("Mark tabs with v, then press A to add them to " + setlist.Name)
+	}
+	return m, nil
+}
+
+// addToSetlist appends tabs to the setlist picked with a in the setlist
+// view, or asks which setlist to add them to.
+func (m Model) addToSetlist(tabs []models.Tab) (tea.Model, tea.Cmd) {
+	setlists, err := m.storage.LoadSetlists()
+	if err != nil {
+		m.statusBar.SetStatus("Error loading setlists: " + err.Error())
+		return m, nil
+	}
+
+	for _, setlist := range setlists {
+		if setlist.ID == m.setlistTarget {
+			m.appendToSetlist(setlist, tabs)
+			return m, m.openSetlists()
+		}
+	}
+	m.setlistTarget = 0
+
+	prompt := fmt.Sprintf("Add %q to which setlist?", tabs[0].Name)
+	if len(tabs) > 1 {
+		prompt = fmt.Sprintf("Add %d tabs to which setlist?", len(tabs))
+	}
+
+	var choices []dialogChoice
+	for i, setlist := range setlists {
+		if i == 9 {
+			break
+		}
+		setlist := setlist
+		choices = append(choices, dialogChoice{key: fmt.Sprint(i + 1), label: setlist.Name, action: func(m *Model) tea.Cmd {
+			m.appendToSetlist(setlist, tabs)
+			return nil
+		}})
+	}
+	name := fmt.Sprintf("Setlist %d", len(setlists)+1)
+	choices = append(choices,
+		dialogChoice{key: "n", label: "New setlist", action: func(m *Model) tea.Cmd {
+			m.appendToSetlist(*models.NewSetlist(name), tabs)
+			return nil
+		}},
+		dialogChoice{key: "esc", label: "Cancel", action: func(m *Model) tea.Cmd {
+			m.statusBar.SetStatus("Cancelled")
+			return nil
+		}},
+	)
+
+	m.confirm = &confirmDialog{title: "Add to setlist", prompt: prompt, choices: choices}
+	return m, nil
+}
+
+func (m *Model) appendToSetlist(setlist models.Setlist, tabs []models.Tab) {
+	for _, tab := range tabs {
+		setlist.Entries = append(setlist.Entries, models.SetlistEntry{TabID: tab.ID})
+	}
+	if err := m.storage.SaveSetlist(&setlist); err != nil {
+		m.statusBar.SetStatus("Error saving setlist: " + err.Error())
+		return
+	}
+
+	m.setlistTarget = 0
+	m.tabBrowser.ClearMarks()
+	m.reloadSetlists()
+	m.setlists.Select(setlist.ID)
+	m.statusBar.SetStatus(fmt.Sprintf("Added %d %s to %s", len(tabs), models.Plural(len(tabs), "tab"), setlist.Name))
+}
+
+// playSetlist plays setlist from entry from to the end. Pressing play again
+// while it plays stops it. Songs whose tab has gone to the trash are skipped.
+func (m *Model) playSetlist(setlist *models.Setlist, from int) tea.Cmd {
+	if m.midiPlayer.IsPlaying() {
+		m.midiPlayer.Stop()
+		m.statusBar.SetStatus("Playback stopped")
+		return nil
+	}
+
+	var songs []midi.Song
+	m.playingSongs = nil
+	for i := from; i < len(setlist.Entries); i++ {
+		entry := setlist.Entries[i]
+		tab, err := m.storage.LoadTab(entry.TabID)
+		if err != nil || tab.DeletedAt != nil {
+			continue
+		}
+		songs = append(songs, midi.Song{Tab: tab, Transpose: entry.PitchOffset(), Tempo: entry.TempoFor(tab)})
+		m.playingSongs = append(m.playingSongs, i)
+	}
+	if len(songs) == 0 {
+		m.statusBar.SetStatus("Nothing to play")
+		return nil
+	}
+
+	if err := m.midiPlayer.PlaySetlist(songs, setlist.Gap); err != nil {
+		m.statusBar.SetStatus("Playback error: " + err.Error())
+		return nil
+	}
+	m.setlists.SetPlaying(setlist.ID, m.playingSongs[0])
+	m.statusBar.SetStatus(fmt.Sprintf("Playing %s (%d %s)", setlist.Name, len(songs), models.Plural(len(songs), "song")))
+	return setlistTick()
+}
+
+func setlistTick() tea.Cmd {
+	return tea.Tick(250*time.Millisecond, func(t time.Time) tea.Msg {
+		return setlistTickMsg(t)
+	})
+}
+
+// updatePlayingSong moves the song marker along with the player and stops
+// ticking once playback ends.
+func (m *Model) updatePlayingSong() tea.Cmd {
+	song, playing := m.midiPlayer.CurrentSong()
+	if !playing || song >= len(m.playingSongs) {
+		m.playingSongs = nil
+		m.setlists.SetPlaying(0, 0)
+		return nil
+	}
+	m.setlists.SetPlaying(m.setlists.PlayingID(), m.playingSongs[song])
+	return setlistTick()
+}
+
+// exportSetlist writes the setlist with all of its tabs to <name>.txt in
+// the working directory.
+func (m *Model) exportSetlist(setlist *models.Setlist) {
+	tabs := make(map[int]*models.Tab)
+	for _, entry := range setlist.Entries {
+		if tab, err := m.storage.LoadTab(entry.TabID); err == nil && tab.DeletedAt == nil {
+			tabs[tab.ID] = tab
+		}
+	}
+
+	path := fileName(setlist.Name) + ".txt"
+	err := writeFile(path, func(f *os.File) error {
+		return export.WriteSetlist(f, setlist, tabs, setlistExportWidth)
+	})
+	if err != nil {
+		m.statusBar.SetStatus("Error exporting setlist: " + err.Error())
+		return
+	}
+	m.statusBar.SetStatus("Exported " + setlist.Name + " to " + path)
+}
+
+func (m Model) renderSetlists() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		Render("Tuitar - Setlists")
+
+	hints := "n: New • r: Rename • dd: Delete • Enter: Songs • a: Add songs • p: Play • s: Stop • x: Export • Esc: Back"
+	if m.setlists.Editing() {
+		hints = "Enter: OK • Esc: Cancel"
+	}
+	help := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Render(hints)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		m.setlists.View(),
+		"",
+		help,
+	)
+}
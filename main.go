@@ -2,24 +2,80 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/Cod-e-Codes/tuitar/internal/export"
+	"github.com/Cod-e-Codes/tuitar/internal/importer"
+	"github.com/Cod-e-Codes/tuitar/internal/models"
 	"github.com/Cod-e-Codes/tuitar/internal/storage"
 	"github.com/Cod-e-Codes/tuitar/internal/ui"
 )
 
 func main() {
+	autosave := flag.Duration("autosave", 0, "save modified tabs at this interval, e.g. 30s (0 disables)")
+	retention := flag.Duration("trash-retention", 30*24*time.Hour, "delete tabs that have been in the trash this long (0 keeps them)")
+	importFiles := flag.Bool("import", false, "import the tab files given as arguments into the library and exit")
+	track := flag.Int("track", 0, "with -import, import only this track (1 is the first) of files holding several")
+	tuning := flag.String("tuning", "", "with -import, the tuning, lowest string first, that notes with only a pitch are fingered for, e.g. \"D A D G B e\" (default standard)")
+	exportFiles := flag.Bool("export", false, "export the tabs given as arguments (IDs or names) to -o, or standard output, and exit")
+	output := flag.String("o", "", "file to export to (default standard output)")
+	format := flag.String("format", "", "export format, text, musicxml, lilypond, svg, pdf, html or chordpro (default from the -o extension, else text)")
+	width := flag.Int("width", 80, "line width exported systems wrap at (0 never wraps)")
+	measureNumbers := flag.Bool("measure-numbers", false, "number the measures of exported tabs")
+	noHeader := flag.Bool("no-header", false, "leave the name, tempo and tuning out of exported tabs")
+	notation := flag.Bool("notation", false, "add a standard notation staff above the tab of LilyPond scores")
+	flag.Parse()
+
 	// Initialize storage
 	storage, err := storage.NewSQLiteStorage("tabs.db")
 	if err != nil {
 		log.Fatal("Failed to initialize storage:", err)
 	}
 
+	if *importFiles {
+		var opts importer.Options
+		if *tuning != "" {
+			var ok bool
+			if opts.Tuning, ok = models.ParseTuning(*tuning); !ok {
+				fmt.Fprintf(os.Stderr, "invalid tuning %q: give six note names, lowest string first\n", *tuning)
+				os.Exit(1)
+			}
+		}
+		if !importTabs(storage, flag.Args(), *track, opts) {
+			os.Exit(1)
+		}
+		return
+	}
+	if *exportFiles {
+		opts := export.Options{
+			ASCII:    export.ASCIIOptions{Width: *width, Header: !*noHeader, MeasureNumbers: *measureNumbers},
+			LilyPond: export.LilyPondOptions{Notation: *notation},
+		}
+		if err := exportTabs(storage, flag.Args(), *output, *format, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Empty old tabs out of the trash; only when started interactively,
+	// so a scripted import or export never deletes anything
+	if *retention > 0 {
+		if _, err := storage.PurgeTrash(time.Now().Add(-*retention)); err != nil {
+			log.Println("Failed to purge trash:", err)
+		}
+	}
+
 	// Create the main application model
-	m := ui.NewModel(storage)
+	m := ui.NewModel(storage).WithAutosave(*autosave)
 
 	// Start the Bubble Tea program
 	p := tea.NewProgram(m, tea.WithAltScreen())
@@ -28,3 +84,112 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// importTabs imports each file into the library, printing the lines the
+// importer could not use. A track above 0 picks one of the tabs of files
+// that hold several. It reports whether every file was imported.
+func importTabs(store storage.Storage, paths []string, track int, opts importer.Options) bool {
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: tuitar -import [-track N] [-tuning TUNING] FILE...")
+		return false
+	}
+
+	ok := true
+	for _, path := range paths {
+		tabs, problems, err := importer.ReadFile(path, opts)
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, p)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			ok = false
+			continue
+		}
+		if track > 0 && len(tabs) > 1 {
+			if track > len(tabs) {
+				fmt.Fprintf(os.Stderr, "%s: no track %d; the file has %d\n", path, track, len(tabs))
+				ok = false
+				continue
+			}
+			tabs = tabs[track-1 : track]
+		}
+		for _, tab := range tabs {
+			if err := store.SaveTab(tab); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+				ok = false
+				break
+			}
+			fmt.Printf("Imported %s as %q (#%d)\n", path, tab.Name, tab.ID)
+		}
+	}
+	return ok
+}
+
+// exportTabs writes the tabs named by args, by ID or by name, to output, or
+// to standard output if it is empty. Without a format name the format
+// follows the extension of output.
+func exportTabs(store storage.Storage, args []string, output, formatName string, opts export.Options) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tuitar -export [-format FORMAT] [-o FILE] TAB...")
+	}
+
+	format := export.FormatFor(output, export.FormatText)
+	if formatName != "" {
+		var err error
+		if format, err = export.ParseFormat(formatName); err != nil {
+			return err
+		}
+	}
+	if format != export.FormatText && len(args) > 1 {
+		return fmt.Errorf("%s holds one tab per file; export the tabs one at a time", format)
+	}
+
+	var tabs []*models.Tab
+	for _, arg := range args {
+		tab, err := findTab(store, arg)
+		if err != nil {
+			return err
+		}
+		tabs = append(tabs, tab)
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	for i, tab := range tabs {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := export.Write(w, tab, format, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findTab looks a tab up by ID, or else by name without regard to case.
+func findTab(store storage.Storage, arg string) (*models.Tab, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		if tab, err := store.LoadTab(id); err == nil && tab.DeletedAt == nil {
+			return tab, nil
+		}
+	}
+
+	tabs, err := store.LoadAllTabs()
+	if err != nil {
+		return nil, err
+	}
+	for i := range tabs {
+		if strings.EqualFold(tabs[i].Name, arg) {
+			return &tabs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no tab %q", arg)
+}